@@ -0,0 +1,2017 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: flight.proto
+
+// The flight search over gRPC. Messages mirror the JSON API (internal/entity), field
+// for field, so both transports return the same search.
+
+package flightv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SearchRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Origin       string                 `protobuf:"bytes,1,opt,name=origin,proto3" json:"origin,omitempty"`
+	Destinations []string               `protobuf:"bytes,2,rep,name=destinations,proto3" json:"destinations,omitempty"`
+	// YYYY-MM-DD
+	DepartureDate string  `protobuf:"bytes,3,opt,name=departure_date,json=departureDate,proto3" json:"departure_date,omitempty"`
+	ReturnDate    *string `protobuf:"bytes,4,opt,name=return_date,json=returnDate,proto3,oneof" json:"return_date,omitempty"`
+	// 1 to 9, defaults to 1 when neither it nor the party per type is set
+	Passengers int32  `protobuf:"varint,5,opt,name=passengers,proto3" json:"passengers,omitempty"`
+	CabinClass string `protobuf:"bytes,6,opt,name=cabin_class,json=cabinClass,proto3" json:"cabin_class,omitempty"`
+	// ISO code every price is converted to, price_min and price_max are in it too
+	DisplayCurrency string `protobuf:"bytes,7,opt,name=display_currency,json=displayCurrency,proto3" json:"display_currency,omitempty"`
+	// Party per passenger type, passengers is the seated total when these are set
+	Adults   int32 `protobuf:"varint,8,opt,name=adults,proto3" json:"adults,omitempty"`
+	Children int32 `protobuf:"varint,9,opt,name=children,proto3" json:"children,omitempty"`
+	Infants  int32 `protobuf:"varint,10,opt,name=infants,proto3" json:"infants,omitempty"`
+	// Filters. Time windows (HH:MM) are in the airport's local time and wrap
+	// midnight when min is later than max. price_min and price_max bound the
+	// total for the whole party.
+	PriceMin    float64  `protobuf:"fixed64,11,opt,name=price_min,json=priceMin,proto3" json:"price_min,omitempty"`
+	PriceMax    float64  `protobuf:"fixed64,12,opt,name=price_max,json=priceMax,proto3" json:"price_max,omitempty"`
+	MaxStops    *int32   `protobuf:"varint,13,opt,name=max_stops,json=maxStops,proto3,oneof" json:"max_stops,omitempty"`
+	Airlines    []string `protobuf:"bytes,14,rep,name=airlines,proto3" json:"airlines,omitempty"`
+	MinDepTime  string   `protobuf:"bytes,15,opt,name=min_dep_time,json=minDepTime,proto3" json:"min_dep_time,omitempty"`
+	MaxDepTime  string   `protobuf:"bytes,16,opt,name=max_dep_time,json=maxDepTime,proto3" json:"max_dep_time,omitempty"`
+	MinArrTime  string   `protobuf:"bytes,17,opt,name=min_arr_time,json=minArrTime,proto3" json:"min_arr_time,omitempty"`
+	MaxArrTime  string   `protobuf:"bytes,18,opt,name=max_arr_time,json=maxArrTime,proto3" json:"max_arr_time,omitempty"`
+	MaxDuration int32    `protobuf:"varint,19,opt,name=max_duration,json=maxDuration,proto3" json:"max_duration,omitempty"`
+	// Layover filters, in minutes
+	MaxLayoverMinutes      int32    `protobuf:"varint,20,opt,name=max_layover_minutes,json=maxLayoverMinutes,proto3" json:"max_layover_minutes,omitempty"`
+	MaxTotalLayoverMinutes int32    `protobuf:"varint,21,opt,name=max_total_layover_minutes,json=maxTotalLayoverMinutes,proto3" json:"max_total_layover_minutes,omitempty"`
+	MinLayoverMinutes      int32    `protobuf:"varint,22,opt,name=min_layover_minutes,json=minLayoverMinutes,proto3" json:"min_layover_minutes,omitempty"`
+	AvoidAirports          []string `protobuf:"bytes,23,rep,name=avoid_airports,json=avoidAirports,proto3" json:"avoid_airports,omitempty"`
+	SortBy                 string   `protobuf:"bytes,24,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	SortOrder              string   `protobuf:"bytes,25,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`
+	// Overrides the configured search deadline
+	MaxWaitMs     int32 `protobuf:"varint,26,opt,name=max_wait_ms,json=maxWaitMs,proto3" json:"max_wait_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchRequest) Reset() {
+	*x = SearchRequest{}
+	mi := &file_flight_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRequest) ProtoMessage() {}
+
+func (x *SearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_flight_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return file_flight_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SearchRequest) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetDestinations() []string {
+	if x != nil {
+		return x.Destinations
+	}
+	return nil
+}
+
+func (x *SearchRequest) GetDepartureDate() string {
+	if x != nil {
+		return x.DepartureDate
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetReturnDate() string {
+	if x != nil && x.ReturnDate != nil {
+		return *x.ReturnDate
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetPassengers() int32 {
+	if x != nil {
+		return x.Passengers
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetCabinClass() string {
+	if x != nil {
+		return x.CabinClass
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetDisplayCurrency() string {
+	if x != nil {
+		return x.DisplayCurrency
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetAdults() int32 {
+	if x != nil {
+		return x.Adults
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetChildren() int32 {
+	if x != nil {
+		return x.Children
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetInfants() int32 {
+	if x != nil {
+		return x.Infants
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetPriceMin() float64 {
+	if x != nil {
+		return x.PriceMin
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetPriceMax() float64 {
+	if x != nil {
+		return x.PriceMax
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetMaxStops() int32 {
+	if x != nil && x.MaxStops != nil {
+		return *x.MaxStops
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetAirlines() []string {
+	if x != nil {
+		return x.Airlines
+	}
+	return nil
+}
+
+func (x *SearchRequest) GetMinDepTime() string {
+	if x != nil {
+		return x.MinDepTime
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetMaxDepTime() string {
+	if x != nil {
+		return x.MaxDepTime
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetMinArrTime() string {
+	if x != nil {
+		return x.MinArrTime
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetMaxArrTime() string {
+	if x != nil {
+		return x.MaxArrTime
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetMaxDuration() int32 {
+	if x != nil {
+		return x.MaxDuration
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetMaxLayoverMinutes() int32 {
+	if x != nil {
+		return x.MaxLayoverMinutes
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetMaxTotalLayoverMinutes() int32 {
+	if x != nil {
+		return x.MaxTotalLayoverMinutes
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetMinLayoverMinutes() int32 {
+	if x != nil {
+		return x.MinLayoverMinutes
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetAvoidAirports() []string {
+	if x != nil {
+		return x.AvoidAirports
+	}
+	return nil
+}
+
+func (x *SearchRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetSortOrder() string {
+	if x != nil {
+		return x.SortOrder
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetMaxWaitMs() int32 {
+	if x != nil {
+		return x.MaxWaitMs
+	}
+	return 0
+}
+
+type SearchResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SearchCriteria *SearchCriteria        `protobuf:"bytes,1,opt,name=search_criteria,json=searchCriteria,proto3" json:"search_criteria,omitempty"`
+	Metadata       *Metadata              `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	BestValueDeal  *Flight                `protobuf:"bytes,3,opt,name=best_value_deal,json=bestValueDeal,proto3" json:"best_value_deal,omitempty"`
+	Flights        []*Flight              `protobuf:"bytes,4,rep,name=flights,proto3" json:"flights,omitempty"`
+	// Round trip only
+	ReturnFlights      []*Flight  `protobuf:"bytes,5,rep,name=return_flights,json=returnFlights,proto3" json:"return_flights,omitempty"`
+	CheapestRoundTrip  *Itinerary `protobuf:"bytes,6,opt,name=cheapest_round_trip,json=cheapestRoundTrip,proto3" json:"cheapest_round_trip,omitempty"`
+	BestValueRoundTrip *Itinerary `protobuf:"bytes,7,opt,name=best_value_round_trip,json=bestValueRoundTrip,proto3" json:"best_value_round_trip,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *SearchResponse) Reset() {
+	*x = SearchResponse{}
+	mi := &file_flight_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResponse) ProtoMessage() {}
+
+func (x *SearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_flight_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResponse.ProtoReflect.Descriptor instead.
+func (*SearchResponse) Descriptor() ([]byte, []int) {
+	return file_flight_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SearchResponse) GetSearchCriteria() *SearchCriteria {
+	if x != nil {
+		return x.SearchCriteria
+	}
+	return nil
+}
+
+func (x *SearchResponse) GetMetadata() *Metadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *SearchResponse) GetBestValueDeal() *Flight {
+	if x != nil {
+		return x.BestValueDeal
+	}
+	return nil
+}
+
+func (x *SearchResponse) GetFlights() []*Flight {
+	if x != nil {
+		return x.Flights
+	}
+	return nil
+}
+
+func (x *SearchResponse) GetReturnFlights() []*Flight {
+	if x != nil {
+		return x.ReturnFlights
+	}
+	return nil
+}
+
+func (x *SearchResponse) GetCheapestRoundTrip() *Itinerary {
+	if x != nil {
+		return x.CheapestRoundTrip
+	}
+	return nil
+}
+
+func (x *SearchResponse) GetBestValueRoundTrip() *Itinerary {
+	if x != nil {
+		return x.BestValueRoundTrip
+	}
+	return nil
+}
+
+// SearchEvent is one step of SearchStream: a provider event per provider, a best
+// value event whenever the best deal so far changes, and a final summary
+type SearchEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*SearchEvent_Provider
+	//	*SearchEvent_BestValue
+	//	*SearchEvent_Summary
+	Event         isSearchEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchEvent) Reset() {
+	*x = SearchEvent{}
+	mi := &file_flight_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchEvent) ProtoMessage() {}
+
+func (x *SearchEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_flight_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchEvent.ProtoReflect.Descriptor instead.
+func (*SearchEvent) Descriptor() ([]byte, []int) {
+	return file_flight_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SearchEvent) GetEvent() isSearchEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *SearchEvent) GetProvider() *ProviderResult {
+	if x != nil {
+		if x, ok := x.Event.(*SearchEvent_Provider); ok {
+			return x.Provider
+		}
+	}
+	return nil
+}
+
+func (x *SearchEvent) GetBestValue() *Flight {
+	if x != nil {
+		if x, ok := x.Event.(*SearchEvent_BestValue); ok {
+			return x.BestValue
+		}
+	}
+	return nil
+}
+
+func (x *SearchEvent) GetSummary() *SearchSummary {
+	if x != nil {
+		if x, ok := x.Event.(*SearchEvent_Summary); ok {
+			return x.Summary
+		}
+	}
+	return nil
+}
+
+type isSearchEvent_Event interface {
+	isSearchEvent_Event()
+}
+
+type SearchEvent_Provider struct {
+	Provider *ProviderResult `protobuf:"bytes,1,opt,name=provider,proto3,oneof"`
+}
+
+type SearchEvent_BestValue struct {
+	BestValue *Flight `protobuf:"bytes,2,opt,name=best_value,json=bestValue,proto3,oneof"`
+}
+
+type SearchEvent_Summary struct {
+	Summary *SearchSummary `protobuf:"bytes,3,opt,name=summary,proto3,oneof"`
+}
+
+func (*SearchEvent_Provider) isSearchEvent_Event() {}
+
+func (*SearchEvent_BestValue) isSearchEvent_Event() {}
+
+func (*SearchEvent_Summary) isSearchEvent_Event() {}
+
+type ProviderResult struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Provider string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	// ok, failed, skipped or timed_out
+	Status string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// fresh, stale or miss
+	CacheStatus string `protobuf:"bytes,3,opt,name=cache_status,json=cacheStatus,proto3" json:"cache_status,omitempty"`
+	Retries     int32  `protobuf:"varint,4,opt,name=retries,proto3" json:"retries,omitempty"`
+	// Already filtered and sorted with the request's options
+	Flights       []*Flight `protobuf:"bytes,5,rep,name=flights,proto3" json:"flights,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProviderResult) Reset() {
+	*x = ProviderResult{}
+	mi := &file_flight_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProviderResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProviderResult) ProtoMessage() {}
+
+func (x *ProviderResult) ProtoReflect() protoreflect.Message {
+	mi := &file_flight_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProviderResult.ProtoReflect.Descriptor instead.
+func (*ProviderResult) Descriptor() ([]byte, []int) {
+	return file_flight_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ProviderResult) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *ProviderResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ProviderResult) GetCacheStatus() string {
+	if x != nil {
+		return x.CacheStatus
+	}
+	return ""
+}
+
+func (x *ProviderResult) GetRetries() int32 {
+	if x != nil {
+		return x.Retries
+	}
+	return 0
+}
+
+func (x *ProviderResult) GetFlights() []*Flight {
+	if x != nil {
+		return x.Flights
+	}
+	return nil
+}
+
+type SearchSummary struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SearchCriteria *SearchCriteria        `protobuf:"bytes,1,opt,name=search_criteria,json=searchCriteria,proto3" json:"search_criteria,omitempty"`
+	Metadata       *Metadata              `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	BestValueDeal  *Flight                `protobuf:"bytes,3,opt,name=best_value_deal,json=bestValueDeal,proto3" json:"best_value_deal,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SearchSummary) Reset() {
+	*x = SearchSummary{}
+	mi := &file_flight_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchSummary) ProtoMessage() {}
+
+func (x *SearchSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_flight_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchSummary.ProtoReflect.Descriptor instead.
+func (*SearchSummary) Descriptor() ([]byte, []int) {
+	return file_flight_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SearchSummary) GetSearchCriteria() *SearchCriteria {
+	if x != nil {
+		return x.SearchCriteria
+	}
+	return nil
+}
+
+func (x *SearchSummary) GetMetadata() *Metadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *SearchSummary) GetBestValueDeal() *Flight {
+	if x != nil {
+		return x.BestValueDeal
+	}
+	return nil
+}
+
+type SearchCriteria struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Origin        string                 `protobuf:"bytes,1,opt,name=origin,proto3" json:"origin,omitempty"`
+	Destination   []string               `protobuf:"bytes,2,rep,name=destination,proto3" json:"destination,omitempty"`
+	DepartureDate string                 `protobuf:"bytes,3,opt,name=departure_date,json=departureDate,proto3" json:"departure_date,omitempty"`
+	ReturnDate    *string                `protobuf:"bytes,4,opt,name=return_date,json=returnDate,proto3,oneof" json:"return_date,omitempty"`
+	Passengers    int32                  `protobuf:"varint,5,opt,name=passengers,proto3" json:"passengers,omitempty"`
+	Adults        int32                  `protobuf:"varint,6,opt,name=adults,proto3" json:"adults,omitempty"`
+	Children      int32                  `protobuf:"varint,7,opt,name=children,proto3" json:"children,omitempty"`
+	Infants       int32                  `protobuf:"varint,8,opt,name=infants,proto3" json:"infants,omitempty"`
+	CabinClass    string                 `protobuf:"bytes,9,opt,name=cabin_class,json=cabinClass,proto3" json:"cabin_class,omitempty"`
+	Currency      string                 `protobuf:"bytes,10,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchCriteria) Reset() {
+	*x = SearchCriteria{}
+	mi := &file_flight_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchCriteria) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchCriteria) ProtoMessage() {}
+
+func (x *SearchCriteria) ProtoReflect() protoreflect.Message {
+	mi := &file_flight_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchCriteria.ProtoReflect.Descriptor instead.
+func (*SearchCriteria) Descriptor() ([]byte, []int) {
+	return file_flight_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SearchCriteria) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *SearchCriteria) GetDestination() []string {
+	if x != nil {
+		return x.Destination
+	}
+	return nil
+}
+
+func (x *SearchCriteria) GetDepartureDate() string {
+	if x != nil {
+		return x.DepartureDate
+	}
+	return ""
+}
+
+func (x *SearchCriteria) GetReturnDate() string {
+	if x != nil && x.ReturnDate != nil {
+		return *x.ReturnDate
+	}
+	return ""
+}
+
+func (x *SearchCriteria) GetPassengers() int32 {
+	if x != nil {
+		return x.Passengers
+	}
+	return 0
+}
+
+func (x *SearchCriteria) GetAdults() int32 {
+	if x != nil {
+		return x.Adults
+	}
+	return 0
+}
+
+func (x *SearchCriteria) GetChildren() int32 {
+	if x != nil {
+		return x.Children
+	}
+	return 0
+}
+
+func (x *SearchCriteria) GetInfants() int32 {
+	if x != nil {
+		return x.Infants
+	}
+	return 0
+}
+
+func (x *SearchCriteria) GetCabinClass() string {
+	if x != nil {
+		return x.CabinClass
+	}
+	return ""
+}
+
+func (x *SearchCriteria) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type Metadata struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	TotalResults       int32                  `protobuf:"varint,1,opt,name=total_results,json=totalResults,proto3" json:"total_results,omitempty"`
+	ProvidersQueried   int32                  `protobuf:"varint,2,opt,name=providers_queried,json=providersQueried,proto3" json:"providers_queried,omitempty"`
+	ProvidersSucceeded int32                  `protobuf:"varint,3,opt,name=providers_succeeded,json=providersSucceeded,proto3" json:"providers_succeeded,omitempty"`
+	ProvidersFailed    int32                  `protobuf:"varint,4,opt,name=providers_failed,json=providersFailed,proto3" json:"providers_failed,omitempty"`
+	SearchTimeMs       int64                  `protobuf:"varint,5,opt,name=search_time_ms,json=searchTimeMs,proto3" json:"search_time_ms,omitempty"`
+	// fresh, stale or miss per provider
+	CacheStatus       map[string]string `protobuf:"bytes,6,rep,name=cache_status,json=cacheStatus,proto3" json:"cache_status,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	CacheHealthy      bool              `protobuf:"varint,7,opt,name=cache_healthy,json=cacheHealthy,proto3" json:"cache_healthy,omitempty"`
+	ProvidersSkipped  []string          `protobuf:"bytes,8,rep,name=providers_skipped,json=providersSkipped,proto3" json:"providers_skipped,omitempty"`
+	ProvidersTimedOut []string          `protobuf:"bytes,9,rep,name=providers_timed_out,json=providersTimedOut,proto3" json:"providers_timed_out,omitempty"`
+	ProviderRetries   map[string]int32  `protobuf:"bytes,10,rep,name=provider_retries,json=providerRetries,proto3" json:"provider_retries,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Metadata) Reset() {
+	*x = Metadata{}
+	mi := &file_flight_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Metadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Metadata) ProtoMessage() {}
+
+func (x *Metadata) ProtoReflect() protoreflect.Message {
+	mi := &file_flight_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Metadata.ProtoReflect.Descriptor instead.
+func (*Metadata) Descriptor() ([]byte, []int) {
+	return file_flight_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Metadata) GetTotalResults() int32 {
+	if x != nil {
+		return x.TotalResults
+	}
+	return 0
+}
+
+func (x *Metadata) GetProvidersQueried() int32 {
+	if x != nil {
+		return x.ProvidersQueried
+	}
+	return 0
+}
+
+func (x *Metadata) GetProvidersSucceeded() int32 {
+	if x != nil {
+		return x.ProvidersSucceeded
+	}
+	return 0
+}
+
+func (x *Metadata) GetProvidersFailed() int32 {
+	if x != nil {
+		return x.ProvidersFailed
+	}
+	return 0
+}
+
+func (x *Metadata) GetSearchTimeMs() int64 {
+	if x != nil {
+		return x.SearchTimeMs
+	}
+	return 0
+}
+
+func (x *Metadata) GetCacheStatus() map[string]string {
+	if x != nil {
+		return x.CacheStatus
+	}
+	return nil
+}
+
+func (x *Metadata) GetCacheHealthy() bool {
+	if x != nil {
+		return x.CacheHealthy
+	}
+	return false
+}
+
+func (x *Metadata) GetProvidersSkipped() []string {
+	if x != nil {
+		return x.ProvidersSkipped
+	}
+	return nil
+}
+
+func (x *Metadata) GetProvidersTimedOut() []string {
+	if x != nil {
+		return x.ProvidersTimedOut
+	}
+	return nil
+}
+
+func (x *Metadata) GetProviderRetries() map[string]int32 {
+	if x != nil {
+		return x.ProviderRetries
+	}
+	return nil
+}
+
+type Itinerary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Legs          []*Flight              `protobuf:"bytes,1,rep,name=legs,proto3" json:"legs,omitempty"`
+	TotalPrice    *PriceDetails          `protobuf:"bytes,2,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
+	TotalDuration *DurationDetails       `protobuf:"bytes,3,opt,name=total_duration,json=totalDuration,proto3" json:"total_duration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Itinerary) Reset() {
+	*x = Itinerary{}
+	mi := &file_flight_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Itinerary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Itinerary) ProtoMessage() {}
+
+func (x *Itinerary) ProtoReflect() protoreflect.Message {
+	mi := &file_flight_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Itinerary.ProtoReflect.Descriptor instead.
+func (*Itinerary) Descriptor() ([]byte, []int) {
+	return file_flight_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Itinerary) GetLegs() []*Flight {
+	if x != nil {
+		return x.Legs
+	}
+	return nil
+}
+
+func (x *Itinerary) GetTotalPrice() *PriceDetails {
+	if x != nil {
+		return x.TotalPrice
+	}
+	return nil
+}
+
+func (x *Itinerary) GetTotalDuration() *DurationDetails {
+	if x != nil {
+		return x.TotalDuration
+	}
+	return nil
+}
+
+type Flight struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Provider       string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	Airline        *AirlineInfo           `protobuf:"bytes,3,opt,name=airline,proto3" json:"airline,omitempty"`
+	FlightNumber   string                 `protobuf:"bytes,4,opt,name=flight_number,json=flightNumber,proto3" json:"flight_number,omitempty"`
+	Departure      *LocationDetails       `protobuf:"bytes,5,opt,name=departure,proto3" json:"departure,omitempty"`
+	Arrival        *LocationDetails       `protobuf:"bytes,6,opt,name=arrival,proto3" json:"arrival,omitempty"`
+	Duration       *DurationDetails       `protobuf:"bytes,7,opt,name=duration,proto3" json:"duration,omitempty"`
+	Stops          int32                  `protobuf:"varint,8,opt,name=stops,proto3" json:"stops,omitempty"`
+	Layovers       []*Layover             `protobuf:"bytes,9,rep,name=layovers,proto3" json:"layovers,omitempty"`
+	Price          *PriceDetails          `protobuf:"bytes,10,opt,name=price,proto3" json:"price,omitempty"`
+	AvailableSeats int32                  `protobuf:"varint,11,opt,name=available_seats,json=availableSeats,proto3" json:"available_seats,omitempty"`
+	// One of economy, premium_economy, business or first; fare_class is what the airline sent
+	CabinClass    string          `protobuf:"bytes,12,opt,name=cabin_class,json=cabinClass,proto3" json:"cabin_class,omitempty"`
+	FareClass     string          `protobuf:"bytes,13,opt,name=fare_class,json=fareClass,proto3" json:"fare_class,omitempty"`
+	Aircraft      *string         `protobuf:"bytes,14,opt,name=aircraft,proto3,oneof" json:"aircraft,omitempty"`
+	Amenities     []string        `protobuf:"bytes,15,rep,name=amenities,proto3" json:"amenities,omitempty"`
+	Baggage       *BaggageDetails `protobuf:"bytes,16,opt,name=baggage,proto3" json:"baggage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Flight) Reset() {
+	*x = Flight{}
+	mi := &file_flight_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Flight) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Flight) ProtoMessage() {}
+
+func (x *Flight) ProtoReflect() protoreflect.Message {
+	mi := &file_flight_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Flight.ProtoReflect.Descriptor instead.
+func (*Flight) Descriptor() ([]byte, []int) {
+	return file_flight_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Flight) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Flight) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Flight) GetAirline() *AirlineInfo {
+	if x != nil {
+		return x.Airline
+	}
+	return nil
+}
+
+func (x *Flight) GetFlightNumber() string {
+	if x != nil {
+		return x.FlightNumber
+	}
+	return ""
+}
+
+func (x *Flight) GetDeparture() *LocationDetails {
+	if x != nil {
+		return x.Departure
+	}
+	return nil
+}
+
+func (x *Flight) GetArrival() *LocationDetails {
+	if x != nil {
+		return x.Arrival
+	}
+	return nil
+}
+
+func (x *Flight) GetDuration() *DurationDetails {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *Flight) GetStops() int32 {
+	if x != nil {
+		return x.Stops
+	}
+	return 0
+}
+
+func (x *Flight) GetLayovers() []*Layover {
+	if x != nil {
+		return x.Layovers
+	}
+	return nil
+}
+
+func (x *Flight) GetPrice() *PriceDetails {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *Flight) GetAvailableSeats() int32 {
+	if x != nil {
+		return x.AvailableSeats
+	}
+	return 0
+}
+
+func (x *Flight) GetCabinClass() string {
+	if x != nil {
+		return x.CabinClass
+	}
+	return ""
+}
+
+func (x *Flight) GetFareClass() string {
+	if x != nil {
+		return x.FareClass
+	}
+	return ""
+}
+
+func (x *Flight) GetAircraft() string {
+	if x != nil && x.Aircraft != nil {
+		return *x.Aircraft
+	}
+	return ""
+}
+
+func (x *Flight) GetAmenities() []string {
+	if x != nil {
+		return x.Amenities
+	}
+	return nil
+}
+
+func (x *Flight) GetBaggage() *BaggageDetails {
+	if x != nil {
+		return x.Baggage
+	}
+	return nil
+}
+
+type AirlineInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AirlineInfo) Reset() {
+	*x = AirlineInfo{}
+	mi := &file_flight_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AirlineInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AirlineInfo) ProtoMessage() {}
+
+func (x *AirlineInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_flight_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AirlineInfo.ProtoReflect.Descriptor instead.
+func (*AirlineInfo) Descriptor() ([]byte, []int) {
+	return file_flight_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AirlineInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AirlineInfo) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type LocationDetails struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Airport string                 `protobuf:"bytes,1,opt,name=airport,proto3" json:"airport,omitempty"`
+	City    string                 `protobuf:"bytes,2,opt,name=city,proto3" json:"city,omitempty"`
+	// RFC 3339 in the airport's local time
+	Datetime  string `protobuf:"bytes,3,opt,name=datetime,proto3" json:"datetime,omitempty"`
+	Timestamp int64  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// IANA name of the airport's zone
+	Timezone      string `protobuf:"bytes,5,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	Code          string `protobuf:"bytes,6,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LocationDetails) Reset() {
+	*x = LocationDetails{}
+	mi := &file_flight_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocationDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocationDetails) ProtoMessage() {}
+
+func (x *LocationDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_flight_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocationDetails.ProtoReflect.Descriptor instead.
+func (*LocationDetails) Descriptor() ([]byte, []int) {
+	return file_flight_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *LocationDetails) GetAirport() string {
+	if x != nil {
+		return x.Airport
+	}
+	return ""
+}
+
+func (x *LocationDetails) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *LocationDetails) GetDatetime() string {
+	if x != nil {
+		return x.Datetime
+	}
+	return ""
+}
+
+func (x *LocationDetails) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *LocationDetails) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *LocationDetails) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type DurationDetails struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TotalMinutes  int32                  `protobuf:"varint,1,opt,name=total_minutes,json=totalMinutes,proto3" json:"total_minutes,omitempty"`
+	Formatted     string                 `protobuf:"bytes,2,opt,name=formatted,proto3" json:"formatted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DurationDetails) Reset() {
+	*x = DurationDetails{}
+	mi := &file_flight_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DurationDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DurationDetails) ProtoMessage() {}
+
+func (x *DurationDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_flight_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DurationDetails.ProtoReflect.Descriptor instead.
+func (*DurationDetails) Descriptor() ([]byte, []int) {
+	return file_flight_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DurationDetails) GetTotalMinutes() int32 {
+	if x != nil {
+		return x.TotalMinutes
+	}
+	return 0
+}
+
+func (x *DurationDetails) GetFormatted() string {
+	if x != nil {
+		return x.Formatted
+	}
+	return ""
+}
+
+// Layover times are only set when the airline sends segment times, RFC 3339
+type Layover struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Airport         string                 `protobuf:"bytes,1,opt,name=airport,proto3" json:"airport,omitempty"`
+	AirportName     string                 `protobuf:"bytes,2,opt,name=airport_name,json=airportName,proto3" json:"airport_name,omitempty"`
+	City            string                 `protobuf:"bytes,3,opt,name=city,proto3" json:"city,omitempty"`
+	DurationMinutes int32                  `protobuf:"varint,4,opt,name=duration_minutes,json=durationMinutes,proto3" json:"duration_minutes,omitempty"`
+	ArrivalTime     *string                `protobuf:"bytes,5,opt,name=arrival_time,json=arrivalTime,proto3,oneof" json:"arrival_time,omitempty"`
+	DepartureTime   *string                `protobuf:"bytes,6,opt,name=departure_time,json=departureTime,proto3,oneof" json:"departure_time,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Layover) Reset() {
+	*x = Layover{}
+	mi := &file_flight_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Layover) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Layover) ProtoMessage() {}
+
+func (x *Layover) ProtoReflect() protoreflect.Message {
+	mi := &file_flight_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Layover.ProtoReflect.Descriptor instead.
+func (*Layover) Descriptor() ([]byte, []int) {
+	return file_flight_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Layover) GetAirport() string {
+	if x != nil {
+		return x.Airport
+	}
+	return ""
+}
+
+func (x *Layover) GetAirportName() string {
+	if x != nil {
+		return x.AirportName
+	}
+	return ""
+}
+
+func (x *Layover) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Layover) GetDurationMinutes() int32 {
+	if x != nil {
+		return x.DurationMinutes
+	}
+	return 0
+}
+
+func (x *Layover) GetArrivalTime() string {
+	if x != nil && x.ArrivalTime != nil {
+		return *x.ArrivalTime
+	}
+	return ""
+}
+
+func (x *Layover) GetDepartureTime() string {
+	if x != nil && x.DepartureTime != nil {
+		return *x.DepartureTime
+	}
+	return ""
+}
+
+// amount is per passenger, total_amount is for the whole party
+type PriceDetails struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Amount         float64                `protobuf:"fixed64,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency       string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	Formatted      string                 `protobuf:"bytes,3,opt,name=formatted,proto3" json:"formatted,omitempty"`
+	Passengers     int32                  `protobuf:"varint,4,opt,name=passengers,proto3" json:"passengers,omitempty"`
+	TotalAmount    float64                `protobuf:"fixed64,5,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
+	TotalFormatted string                 `protobuf:"bytes,6,opt,name=total_formatted,json=totalFormatted,proto3" json:"total_formatted,omitempty"`
+	// The airline's quote, set when the price was converted to another currency
+	Original       *OriginalPrice   `protobuf:"bytes,7,opt,name=original,proto3" json:"original,omitempty"`
+	Breakdown      *FareBreakdown   `protobuf:"bytes,8,opt,name=breakdown,proto3" json:"breakdown,omitempty"`
+	PassengerFares []*PassengerFare `protobuf:"bytes,9,rep,name=passenger_fares,json=passengerFares,proto3" json:"passenger_fares,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PriceDetails) Reset() {
+	*x = PriceDetails{}
+	mi := &file_flight_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceDetails) ProtoMessage() {}
+
+func (x *PriceDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_flight_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceDetails.ProtoReflect.Descriptor instead.
+func (*PriceDetails) Descriptor() ([]byte, []int) {
+	return file_flight_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PriceDetails) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PriceDetails) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PriceDetails) GetFormatted() string {
+	if x != nil {
+		return x.Formatted
+	}
+	return ""
+}
+
+func (x *PriceDetails) GetPassengers() int32 {
+	if x != nil {
+		return x.Passengers
+	}
+	return 0
+}
+
+func (x *PriceDetails) GetTotalAmount() float64 {
+	if x != nil {
+		return x.TotalAmount
+	}
+	return 0
+}
+
+func (x *PriceDetails) GetTotalFormatted() string {
+	if x != nil {
+		return x.TotalFormatted
+	}
+	return ""
+}
+
+func (x *PriceDetails) GetOriginal() *OriginalPrice {
+	if x != nil {
+		return x.Original
+	}
+	return nil
+}
+
+func (x *PriceDetails) GetBreakdown() *FareBreakdown {
+	if x != nil {
+		return x.Breakdown
+	}
+	return nil
+}
+
+func (x *PriceDetails) GetPassengerFares() []*PassengerFare {
+	if x != nil {
+		return x.PassengerFares
+	}
+	return nil
+}
+
+type OriginalPrice struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Amount        float64                `protobuf:"fixed64,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	Formatted     string                 `protobuf:"bytes,3,opt,name=formatted,proto3" json:"formatted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OriginalPrice) Reset() {
+	*x = OriginalPrice{}
+	mi := &file_flight_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OriginalPrice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OriginalPrice) ProtoMessage() {}
+
+func (x *OriginalPrice) ProtoReflect() protoreflect.Message {
+	mi := &file_flight_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OriginalPrice.ProtoReflect.Descriptor instead.
+func (*OriginalPrice) Descriptor() ([]byte, []int) {
+	return file_flight_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *OriginalPrice) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *OriginalPrice) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *OriginalPrice) GetFormatted() string {
+	if x != nil {
+		return x.Formatted
+	}
+	return ""
+}
+
+type FareBreakdown struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// itemized or total_only
+	Source        string  `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	BaseFare      float64 `protobuf:"fixed64,2,opt,name=base_fare,json=baseFare,proto3" json:"base_fare,omitempty"`
+	Taxes         float64 `protobuf:"fixed64,3,opt,name=taxes,proto3" json:"taxes,omitempty"`
+	Surcharges    float64 `protobuf:"fixed64,4,opt,name=surcharges,proto3" json:"surcharges,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FareBreakdown) Reset() {
+	*x = FareBreakdown{}
+	mi := &file_flight_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FareBreakdown) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FareBreakdown) ProtoMessage() {}
+
+func (x *FareBreakdown) ProtoReflect() protoreflect.Message {
+	mi := &file_flight_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FareBreakdown.ProtoReflect.Descriptor instead.
+func (*FareBreakdown) Descriptor() ([]byte, []int) {
+	return file_flight_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *FareBreakdown) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *FareBreakdown) GetBaseFare() float64 {
+	if x != nil {
+		return x.BaseFare
+	}
+	return 0
+}
+
+func (x *FareBreakdown) GetTaxes() float64 {
+	if x != nil {
+		return x.Taxes
+	}
+	return 0
+}
+
+func (x *FareBreakdown) GetSurcharges() float64 {
+	if x != nil {
+		return x.Surcharges
+	}
+	return 0
+}
+
+type PassengerFare struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ADT, CHD or INF
+	Type           string  `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Count          int32   `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Amount         float64 `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Formatted      string  `protobuf:"bytes,4,opt,name=formatted,proto3" json:"formatted,omitempty"`
+	TotalAmount    float64 `protobuf:"fixed64,5,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
+	TotalFormatted string  `protobuf:"bytes,6,opt,name=total_formatted,json=totalFormatted,proto3" json:"total_formatted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PassengerFare) Reset() {
+	*x = PassengerFare{}
+	mi := &file_flight_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PassengerFare) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PassengerFare) ProtoMessage() {}
+
+func (x *PassengerFare) ProtoReflect() protoreflect.Message {
+	mi := &file_flight_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PassengerFare.ProtoReflect.Descriptor instead.
+func (*PassengerFare) Descriptor() ([]byte, []int) {
+	return file_flight_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PassengerFare) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PassengerFare) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *PassengerFare) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PassengerFare) GetFormatted() string {
+	if x != nil {
+		return x.Formatted
+	}
+	return ""
+}
+
+func (x *PassengerFare) GetTotalAmount() float64 {
+	if x != nil {
+		return x.TotalAmount
+	}
+	return 0
+}
+
+func (x *PassengerFare) GetTotalFormatted() string {
+	if x != nil {
+		return x.TotalFormatted
+	}
+	return ""
+}
+
+type BaggageDetails struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CarryOn       string                 `protobuf:"bytes,1,opt,name=carry_on,json=carryOn,proto3" json:"carry_on,omitempty"`
+	Checked       string                 `protobuf:"bytes,2,opt,name=checked,proto3" json:"checked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BaggageDetails) Reset() {
+	*x = BaggageDetails{}
+	mi := &file_flight_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BaggageDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BaggageDetails) ProtoMessage() {}
+
+func (x *BaggageDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_flight_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BaggageDetails.ProtoReflect.Descriptor instead.
+func (*BaggageDetails) Descriptor() ([]byte, []int) {
+	return file_flight_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *BaggageDetails) GetCarryOn() string {
+	if x != nil {
+		return x.CarryOn
+	}
+	return ""
+}
+
+func (x *BaggageDetails) GetChecked() string {
+	if x != nil {
+		return x.Checked
+	}
+	return ""
+}
+
+var File_flight_proto protoreflect.FileDescriptor
+
+const file_flight_proto_rawDesc = "" +
+	"\n" +
+	"\fflight.proto\x12\tflight.v1\"\xad\a\n" +
+	"\rSearchRequest\x12\x16\n" +
+	"\x06origin\x18\x01 \x01(\tR\x06origin\x12\"\n" +
+	"\fdestinations\x18\x02 \x03(\tR\fdestinations\x12%\n" +
+	"\x0edeparture_date\x18\x03 \x01(\tR\rdepartureDate\x12$\n" +
+	"\vreturn_date\x18\x04 \x01(\tH\x00R\n" +
+	"returnDate\x88\x01\x01\x12\x1e\n" +
+	"\n" +
+	"passengers\x18\x05 \x01(\x05R\n" +
+	"passengers\x12\x1f\n" +
+	"\vcabin_class\x18\x06 \x01(\tR\n" +
+	"cabinClass\x12)\n" +
+	"\x10display_currency\x18\a \x01(\tR\x0fdisplayCurrency\x12\x16\n" +
+	"\x06adults\x18\b \x01(\x05R\x06adults\x12\x1a\n" +
+	"\bchildren\x18\t \x01(\x05R\bchildren\x12\x18\n" +
+	"\ainfants\x18\n" +
+	" \x01(\x05R\ainfants\x12\x1b\n" +
+	"\tprice_min\x18\v \x01(\x01R\bpriceMin\x12\x1b\n" +
+	"\tprice_max\x18\f \x01(\x01R\bpriceMax\x12 \n" +
+	"\tmax_stops\x18\r \x01(\x05H\x01R\bmaxStops\x88\x01\x01\x12\x1a\n" +
+	"\bairlines\x18\x0e \x03(\tR\bairlines\x12 \n" +
+	"\fmin_dep_time\x18\x0f \x01(\tR\n" +
+	"minDepTime\x12 \n" +
+	"\fmax_dep_time\x18\x10 \x01(\tR\n" +
+	"maxDepTime\x12 \n" +
+	"\fmin_arr_time\x18\x11 \x01(\tR\n" +
+	"minArrTime\x12 \n" +
+	"\fmax_arr_time\x18\x12 \x01(\tR\n" +
+	"maxArrTime\x12!\n" +
+	"\fmax_duration\x18\x13 \x01(\x05R\vmaxDuration\x12.\n" +
+	"\x13max_layover_minutes\x18\x14 \x01(\x05R\x11maxLayoverMinutes\x129\n" +
+	"\x19max_total_layover_minutes\x18\x15 \x01(\x05R\x16maxTotalLayoverMinutes\x12.\n" +
+	"\x13min_layover_minutes\x18\x16 \x01(\x05R\x11minLayoverMinutes\x12%\n" +
+	"\x0eavoid_airports\x18\x17 \x03(\tR\ravoidAirports\x12\x17\n" +
+	"\asort_by\x18\x18 \x01(\tR\x06sortBy\x12\x1d\n" +
+	"\n" +
+	"sort_order\x18\x19 \x01(\tR\tsortOrder\x12\x1e\n" +
+	"\vmax_wait_ms\x18\x1a \x01(\x05R\tmaxWaitMsB\x0e\n" +
+	"\f_return_dateB\f\n" +
+	"\n" +
+	"_max_stops\"\xb6\x03\n" +
+	"\x0eSearchResponse\x12B\n" +
+	"\x0fsearch_criteria\x18\x01 \x01(\v2\x19.flight.v1.SearchCriteriaR\x0esearchCriteria\x12/\n" +
+	"\bmetadata\x18\x02 \x01(\v2\x13.flight.v1.MetadataR\bmetadata\x129\n" +
+	"\x0fbest_value_deal\x18\x03 \x01(\v2\x11.flight.v1.FlightR\rbestValueDeal\x12+\n" +
+	"\aflights\x18\x04 \x03(\v2\x11.flight.v1.FlightR\aflights\x128\n" +
+	"\x0ereturn_flights\x18\x05 \x03(\v2\x11.flight.v1.FlightR\rreturnFlights\x12D\n" +
+	"\x13cheapest_round_trip\x18\x06 \x01(\v2\x14.flight.v1.ItineraryR\x11cheapestRoundTrip\x12G\n" +
+	"\x15best_value_round_trip\x18\a \x01(\v2\x14.flight.v1.ItineraryR\x12bestValueRoundTrip\"\xb9\x01\n" +
+	"\vSearchEvent\x127\n" +
+	"\bprovider\x18\x01 \x01(\v2\x19.flight.v1.ProviderResultH\x00R\bprovider\x122\n" +
+	"\n" +
+	"best_value\x18\x02 \x01(\v2\x11.flight.v1.FlightH\x00R\tbestValue\x124\n" +
+	"\asummary\x18\x03 \x01(\v2\x18.flight.v1.SearchSummaryH\x00R\asummaryB\a\n" +
+	"\x05event\"\xae\x01\n" +
+	"\x0eProviderResult\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12!\n" +
+	"\fcache_status\x18\x03 \x01(\tR\vcacheStatus\x12\x18\n" +
+	"\aretries\x18\x04 \x01(\x05R\aretries\x12+\n" +
+	"\aflights\x18\x05 \x03(\v2\x11.flight.v1.FlightR\aflights\"\xbf\x01\n" +
+	"\rSearchSummary\x12B\n" +
+	"\x0fsearch_criteria\x18\x01 \x01(\v2\x19.flight.v1.SearchCriteriaR\x0esearchCriteria\x12/\n" +
+	"\bmetadata\x18\x02 \x01(\v2\x13.flight.v1.MetadataR\bmetadata\x129\n" +
+	"\x0fbest_value_deal\x18\x03 \x01(\v2\x11.flight.v1.FlightR\rbestValueDeal\"\xd2\x02\n" +
+	"\x0eSearchCriteria\x12\x16\n" +
+	"\x06origin\x18\x01 \x01(\tR\x06origin\x12 \n" +
+	"\vdestination\x18\x02 \x03(\tR\vdestination\x12%\n" +
+	"\x0edeparture_date\x18\x03 \x01(\tR\rdepartureDate\x12$\n" +
+	"\vreturn_date\x18\x04 \x01(\tH\x00R\n" +
+	"returnDate\x88\x01\x01\x12\x1e\n" +
+	"\n" +
+	"passengers\x18\x05 \x01(\x05R\n" +
+	"passengers\x12\x16\n" +
+	"\x06adults\x18\x06 \x01(\x05R\x06adults\x12\x1a\n" +
+	"\bchildren\x18\a \x01(\x05R\bchildren\x12\x18\n" +
+	"\ainfants\x18\b \x01(\x05R\ainfants\x12\x1f\n" +
+	"\vcabin_class\x18\t \x01(\tR\n" +
+	"cabinClass\x12\x1a\n" +
+	"\bcurrency\x18\n" +
+	" \x01(\tR\bcurrencyB\x0e\n" +
+	"\f_return_date\"\x82\x05\n" +
+	"\bMetadata\x12#\n" +
+	"\rtotal_results\x18\x01 \x01(\x05R\ftotalResults\x12+\n" +
+	"\x11providers_queried\x18\x02 \x01(\x05R\x10providersQueried\x12/\n" +
+	"\x13providers_succeeded\x18\x03 \x01(\x05R\x12providersSucceeded\x12)\n" +
+	"\x10providers_failed\x18\x04 \x01(\x05R\x0fprovidersFailed\x12$\n" +
+	"\x0esearch_time_ms\x18\x05 \x01(\x03R\fsearchTimeMs\x12G\n" +
+	"\fcache_status\x18\x06 \x03(\v2$.flight.v1.Metadata.CacheStatusEntryR\vcacheStatus\x12#\n" +
+	"\rcache_healthy\x18\a \x01(\bR\fcacheHealthy\x12+\n" +
+	"\x11providers_skipped\x18\b \x03(\tR\x10providersSkipped\x12.\n" +
+	"\x13providers_timed_out\x18\t \x03(\tR\x11providersTimedOut\x12S\n" +
+	"\x10provider_retries\x18\n" +
+	" \x03(\v2(.flight.v1.Metadata.ProviderRetriesEntryR\x0fproviderRetries\x1a>\n" +
+	"\x10CacheStatusEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aB\n" +
+	"\x14ProviderRetriesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"\xaf\x01\n" +
+	"\tItinerary\x12%\n" +
+	"\x04legs\x18\x01 \x03(\v2\x11.flight.v1.FlightR\x04legs\x128\n" +
+	"\vtotal_price\x18\x02 \x01(\v2\x17.flight.v1.PriceDetailsR\n" +
+	"totalPrice\x12A\n" +
+	"\x0etotal_duration\x18\x03 \x01(\v2\x1a.flight.v1.DurationDetailsR\rtotalDuration\"\x92\x05\n" +
+	"\x06Flight\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x120\n" +
+	"\aairline\x18\x03 \x01(\v2\x16.flight.v1.AirlineInfoR\aairline\x12#\n" +
+	"\rflight_number\x18\x04 \x01(\tR\fflightNumber\x128\n" +
+	"\tdeparture\x18\x05 \x01(\v2\x1a.flight.v1.LocationDetailsR\tdeparture\x124\n" +
+	"\aarrival\x18\x06 \x01(\v2\x1a.flight.v1.LocationDetailsR\aarrival\x126\n" +
+	"\bduration\x18\a \x01(\v2\x1a.flight.v1.DurationDetailsR\bduration\x12\x14\n" +
+	"\x05stops\x18\b \x01(\x05R\x05stops\x12.\n" +
+	"\blayovers\x18\t \x03(\v2\x12.flight.v1.LayoverR\blayovers\x12-\n" +
+	"\x05price\x18\n" +
+	" \x01(\v2\x17.flight.v1.PriceDetailsR\x05price\x12'\n" +
+	"\x0favailable_seats\x18\v \x01(\x05R\x0eavailableSeats\x12\x1f\n" +
+	"\vcabin_class\x18\f \x01(\tR\n" +
+	"cabinClass\x12\x1d\n" +
+	"\n" +
+	"fare_class\x18\r \x01(\tR\tfareClass\x12\x1f\n" +
+	"\baircraft\x18\x0e \x01(\tH\x00R\baircraft\x88\x01\x01\x12\x1c\n" +
+	"\tamenities\x18\x0f \x03(\tR\tamenities\x123\n" +
+	"\abaggage\x18\x10 \x01(\v2\x19.flight.v1.BaggageDetailsR\abaggageB\v\n" +
+	"\t_aircraft\"5\n" +
+	"\vAirlineInfo\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\"\xa9\x01\n" +
+	"\x0fLocationDetails\x12\x18\n" +
+	"\aairport\x18\x01 \x01(\tR\aairport\x12\x12\n" +
+	"\x04city\x18\x02 \x01(\tR\x04city\x12\x1a\n" +
+	"\bdatetime\x18\x03 \x01(\tR\bdatetime\x12\x1c\n" +
+	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp\x12\x1a\n" +
+	"\btimezone\x18\x05 \x01(\tR\btimezone\x12\x12\n" +
+	"\x04code\x18\x06 \x01(\tR\x04code\"T\n" +
+	"\x0fDurationDetails\x12#\n" +
+	"\rtotal_minutes\x18\x01 \x01(\x05R\ftotalMinutes\x12\x1c\n" +
+	"\tformatted\x18\x02 \x01(\tR\tformatted\"\xfd\x01\n" +
+	"\aLayover\x12\x18\n" +
+	"\aairport\x18\x01 \x01(\tR\aairport\x12!\n" +
+	"\fairport_name\x18\x02 \x01(\tR\vairportName\x12\x12\n" +
+	"\x04city\x18\x03 \x01(\tR\x04city\x12)\n" +
+	"\x10duration_minutes\x18\x04 \x01(\x05R\x0fdurationMinutes\x12&\n" +
+	"\farrival_time\x18\x05 \x01(\tH\x00R\varrivalTime\x88\x01\x01\x12*\n" +
+	"\x0edeparture_time\x18\x06 \x01(\tH\x01R\rdepartureTime\x88\x01\x01B\x0f\n" +
+	"\r_arrival_timeB\x11\n" +
+	"\x0f_departure_time\"\xfd\x02\n" +
+	"\fPriceDetails\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x01R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12\x1c\n" +
+	"\tformatted\x18\x03 \x01(\tR\tformatted\x12\x1e\n" +
+	"\n" +
+	"passengers\x18\x04 \x01(\x05R\n" +
+	"passengers\x12!\n" +
+	"\ftotal_amount\x18\x05 \x01(\x01R\vtotalAmount\x12'\n" +
+	"\x0ftotal_formatted\x18\x06 \x01(\tR\x0etotalFormatted\x124\n" +
+	"\boriginal\x18\a \x01(\v2\x18.flight.v1.OriginalPriceR\boriginal\x126\n" +
+	"\tbreakdown\x18\b \x01(\v2\x18.flight.v1.FareBreakdownR\tbreakdown\x12A\n" +
+	"\x0fpassenger_fares\x18\t \x03(\v2\x18.flight.v1.PassengerFareR\x0epassengerFares\"a\n" +
+	"\rOriginalPrice\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x01R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12\x1c\n" +
+	"\tformatted\x18\x03 \x01(\tR\tformatted\"z\n" +
+	"\rFareBreakdown\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x1b\n" +
+	"\tbase_fare\x18\x02 \x01(\x01R\bbaseFare\x12\x14\n" +
+	"\x05taxes\x18\x03 \x01(\x01R\x05taxes\x12\x1e\n" +
+	"\n" +
+	"surcharges\x18\x04 \x01(\x01R\n" +
+	"surcharges\"\xbb\x01\n" +
+	"\rPassengerFare\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x01R\x06amount\x12\x1c\n" +
+	"\tformatted\x18\x04 \x01(\tR\tformatted\x12!\n" +
+	"\ftotal_amount\x18\x05 \x01(\x01R\vtotalAmount\x12'\n" +
+	"\x0ftotal_formatted\x18\x06 \x01(\tR\x0etotalFormatted\"E\n" +
+	"\x0eBaggageDetails\x12\x19\n" +
+	"\bcarry_on\x18\x01 \x01(\tR\acarryOn\x12\x18\n" +
+	"\achecked\x18\x02 \x01(\tR\achecked2\x92\x01\n" +
+	"\rFlightService\x12=\n" +
+	"\x06Search\x12\x18.flight.v1.SearchRequest\x1a\x19.flight.v1.SearchResponse\x12B\n" +
+	"\fSearchStream\x12\x18.flight.v1.SearchRequest\x1a\x16.flight.v1.SearchEvent0\x01B*Z(flight-aggregator/api/flight/v1;flightv1b\x06proto3"
+
+var (
+	file_flight_proto_rawDescOnce sync.Once
+	file_flight_proto_rawDescData []byte
+)
+
+func file_flight_proto_rawDescGZIP() []byte {
+	file_flight_proto_rawDescOnce.Do(func() {
+		file_flight_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_flight_proto_rawDesc), len(file_flight_proto_rawDesc)))
+	})
+	return file_flight_proto_rawDescData
+}
+
+var file_flight_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_flight_proto_goTypes = []any{
+	(*SearchRequest)(nil),   // 0: flight.v1.SearchRequest
+	(*SearchResponse)(nil),  // 1: flight.v1.SearchResponse
+	(*SearchEvent)(nil),     // 2: flight.v1.SearchEvent
+	(*ProviderResult)(nil),  // 3: flight.v1.ProviderResult
+	(*SearchSummary)(nil),   // 4: flight.v1.SearchSummary
+	(*SearchCriteria)(nil),  // 5: flight.v1.SearchCriteria
+	(*Metadata)(nil),        // 6: flight.v1.Metadata
+	(*Itinerary)(nil),       // 7: flight.v1.Itinerary
+	(*Flight)(nil),          // 8: flight.v1.Flight
+	(*AirlineInfo)(nil),     // 9: flight.v1.AirlineInfo
+	(*LocationDetails)(nil), // 10: flight.v1.LocationDetails
+	(*DurationDetails)(nil), // 11: flight.v1.DurationDetails
+	(*Layover)(nil),         // 12: flight.v1.Layover
+	(*PriceDetails)(nil),    // 13: flight.v1.PriceDetails
+	(*OriginalPrice)(nil),   // 14: flight.v1.OriginalPrice
+	(*FareBreakdown)(nil),   // 15: flight.v1.FareBreakdown
+	(*PassengerFare)(nil),   // 16: flight.v1.PassengerFare
+	(*BaggageDetails)(nil),  // 17: flight.v1.BaggageDetails
+	nil,                     // 18: flight.v1.Metadata.CacheStatusEntry
+	nil,                     // 19: flight.v1.Metadata.ProviderRetriesEntry
+}
+var file_flight_proto_depIdxs = []int32{
+	5,  // 0: flight.v1.SearchResponse.search_criteria:type_name -> flight.v1.SearchCriteria
+	6,  // 1: flight.v1.SearchResponse.metadata:type_name -> flight.v1.Metadata
+	8,  // 2: flight.v1.SearchResponse.best_value_deal:type_name -> flight.v1.Flight
+	8,  // 3: flight.v1.SearchResponse.flights:type_name -> flight.v1.Flight
+	8,  // 4: flight.v1.SearchResponse.return_flights:type_name -> flight.v1.Flight
+	7,  // 5: flight.v1.SearchResponse.cheapest_round_trip:type_name -> flight.v1.Itinerary
+	7,  // 6: flight.v1.SearchResponse.best_value_round_trip:type_name -> flight.v1.Itinerary
+	3,  // 7: flight.v1.SearchEvent.provider:type_name -> flight.v1.ProviderResult
+	8,  // 8: flight.v1.SearchEvent.best_value:type_name -> flight.v1.Flight
+	4,  // 9: flight.v1.SearchEvent.summary:type_name -> flight.v1.SearchSummary
+	8,  // 10: flight.v1.ProviderResult.flights:type_name -> flight.v1.Flight
+	5,  // 11: flight.v1.SearchSummary.search_criteria:type_name -> flight.v1.SearchCriteria
+	6,  // 12: flight.v1.SearchSummary.metadata:type_name -> flight.v1.Metadata
+	8,  // 13: flight.v1.SearchSummary.best_value_deal:type_name -> flight.v1.Flight
+	18, // 14: flight.v1.Metadata.cache_status:type_name -> flight.v1.Metadata.CacheStatusEntry
+	19, // 15: flight.v1.Metadata.provider_retries:type_name -> flight.v1.Metadata.ProviderRetriesEntry
+	8,  // 16: flight.v1.Itinerary.legs:type_name -> flight.v1.Flight
+	13, // 17: flight.v1.Itinerary.total_price:type_name -> flight.v1.PriceDetails
+	11, // 18: flight.v1.Itinerary.total_duration:type_name -> flight.v1.DurationDetails
+	9,  // 19: flight.v1.Flight.airline:type_name -> flight.v1.AirlineInfo
+	10, // 20: flight.v1.Flight.departure:type_name -> flight.v1.LocationDetails
+	10, // 21: flight.v1.Flight.arrival:type_name -> flight.v1.LocationDetails
+	11, // 22: flight.v1.Flight.duration:type_name -> flight.v1.DurationDetails
+	12, // 23: flight.v1.Flight.layovers:type_name -> flight.v1.Layover
+	13, // 24: flight.v1.Flight.price:type_name -> flight.v1.PriceDetails
+	17, // 25: flight.v1.Flight.baggage:type_name -> flight.v1.BaggageDetails
+	14, // 26: flight.v1.PriceDetails.original:type_name -> flight.v1.OriginalPrice
+	15, // 27: flight.v1.PriceDetails.breakdown:type_name -> flight.v1.FareBreakdown
+	16, // 28: flight.v1.PriceDetails.passenger_fares:type_name -> flight.v1.PassengerFare
+	0,  // 29: flight.v1.FlightService.Search:input_type -> flight.v1.SearchRequest
+	0,  // 30: flight.v1.FlightService.SearchStream:input_type -> flight.v1.SearchRequest
+	1,  // 31: flight.v1.FlightService.Search:output_type -> flight.v1.SearchResponse
+	2,  // 32: flight.v1.FlightService.SearchStream:output_type -> flight.v1.SearchEvent
+	31, // [31:33] is the sub-list for method output_type
+	29, // [29:31] is the sub-list for method input_type
+	29, // [29:29] is the sub-list for extension type_name
+	29, // [29:29] is the sub-list for extension extendee
+	0,  // [0:29] is the sub-list for field type_name
+}
+
+func init() { file_flight_proto_init() }
+func file_flight_proto_init() {
+	if File_flight_proto != nil {
+		return
+	}
+	file_flight_proto_msgTypes[0].OneofWrappers = []any{}
+	file_flight_proto_msgTypes[2].OneofWrappers = []any{
+		(*SearchEvent_Provider)(nil),
+		(*SearchEvent_BestValue)(nil),
+		(*SearchEvent_Summary)(nil),
+	}
+	file_flight_proto_msgTypes[5].OneofWrappers = []any{}
+	file_flight_proto_msgTypes[8].OneofWrappers = []any{}
+	file_flight_proto_msgTypes[12].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_flight_proto_rawDesc), len(file_flight_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   20,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_flight_proto_goTypes,
+		DependencyIndexes: file_flight_proto_depIdxs,
+		MessageInfos:      file_flight_proto_msgTypes,
+	}.Build()
+	File_flight_proto = out.File
+	file_flight_proto_goTypes = nil
+	file_flight_proto_depIdxs = nil
+}
@@ -0,0 +1,176 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: flight.proto
+
+// The flight search over gRPC. Messages mirror the JSON API (internal/entity), field
+// for field, so both transports return the same search.
+
+package flightv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	FlightService_Search_FullMethodName       = "/flight.v1.FlightService/Search"
+	FlightService_SearchStream_FullMethodName = "/flight.v1.FlightService/SearchStream"
+)
+
+// FlightServiceClient is the client API for FlightService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type FlightServiceClient interface {
+	// Search runs a one-way or round trip search, like POST /v1/flights/search.
+	// The call deadline bounds the search and its provider calls, providers that have
+	// not answered shortly before it are reported in metadata.providers_timed_out.
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	// SearchStream runs a one-way search and sends each provider's flights as soon
+	// as they arrive, like GET /v1/flights/search/stream.
+	SearchStream(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SearchEvent], error)
+}
+
+type flightServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFlightServiceClient(cc grpc.ClientConnInterface) FlightServiceClient {
+	return &flightServiceClient{cc}
+}
+
+func (c *flightServiceClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, FlightService_Search_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flightServiceClient) SearchStream(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SearchEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FlightService_ServiceDesc.Streams[0], FlightService_SearchStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SearchRequest, SearchEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FlightService_SearchStreamClient = grpc.ServerStreamingClient[SearchEvent]
+
+// FlightServiceServer is the server API for FlightService service.
+// All implementations must embed UnimplementedFlightServiceServer
+// for forward compatibility.
+type FlightServiceServer interface {
+	// Search runs a one-way or round trip search, like POST /v1/flights/search.
+	// The call deadline bounds the search and its provider calls, providers that have
+	// not answered shortly before it are reported in metadata.providers_timed_out.
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	// SearchStream runs a one-way search and sends each provider's flights as soon
+	// as they arrive, like GET /v1/flights/search/stream.
+	SearchStream(*SearchRequest, grpc.ServerStreamingServer[SearchEvent]) error
+	mustEmbedUnimplementedFlightServiceServer()
+}
+
+// UnimplementedFlightServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFlightServiceServer struct{}
+
+func (UnimplementedFlightServiceServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedFlightServiceServer) SearchStream(*SearchRequest, grpc.ServerStreamingServer[SearchEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method SearchStream not implemented")
+}
+func (UnimplementedFlightServiceServer) mustEmbedUnimplementedFlightServiceServer() {}
+func (UnimplementedFlightServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeFlightServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FlightServiceServer will
+// result in compilation errors.
+type UnsafeFlightServiceServer interface {
+	mustEmbedUnimplementedFlightServiceServer()
+}
+
+func RegisterFlightServiceServer(s grpc.ServiceRegistrar, srv FlightServiceServer) {
+	// If the following call pancis, it indicates UnimplementedFlightServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FlightService_ServiceDesc, srv)
+}
+
+func _FlightService_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlightServiceServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlightService_Search_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlightServiceServer).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlightService_SearchStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FlightServiceServer).SearchStream(m, &grpc.GenericServerStream[SearchRequest, SearchEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FlightService_SearchStreamServer = grpc.ServerStreamingServer[SearchEvent]
+
+// FlightService_ServiceDesc is the grpc.ServiceDesc for FlightService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FlightService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "flight.v1.FlightService",
+	HandlerType: (*FlightServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Search",
+			Handler:    _FlightService_Search_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SearchStream",
+			Handler:       _FlightService_SearchStream_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "flight.proto",
+}
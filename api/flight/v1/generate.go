@@ -0,0 +1,5 @@
+// Package flightv1 is the gRPC API of the flight search, generated from flight.proto.
+package flightv1
+
+// buf, protoc-gen-go and protoc-gen-go-grpc are pinned in tools/go.mod
+//go:generate go tool -modfile=../../../tools/go.mod buf generate
@@ -1,39 +1,187 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	logger "flight-aggregator/internal/common"
+	"flight-aggregator/internal/config"
 	"flight-aggregator/internal/controller"
+	"flight-aggregator/internal/grpcserver"
 	"flight-aggregator/internal/redis"
 	"flight-aggregator/internal/service"
-	"flight-aggregator/internal/service/airasia"
-	"flight-aggregator/internal/service/batikair"
-	"flight-aggregator/internal/service/garuda"
-	"flight-aggregator/internal/service/lionair"
+	"flight-aggregator/internal/service/airport"
+	"flight-aggregator/internal/service/fx"
+	"flight-aggregator/internal/service/provider"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"google.golang.org/grpc"
+
+	// every airline the app can run registers itself, config.providers picks
+	// which ones and in which order. Import a new airline here.
+	_ "flight-aggregator/internal/service/airasia"
+	_ "flight-aggregator/internal/service/batikair"
+	_ "flight-aggregator/internal/service/garuda"
+	_ "flight-aggregator/internal/service/lionair"
 )
 
 func main() {
-	log := logger.Init()
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
 
-	log.Info("Starting the app")
+	// the one logger of the app, every component gets it from here
+	log, err := logger.New(cfg.LoggerOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create logger: %v\n", err)
+		os.Exit(1)
+	}
+	log.Info("starting the app", "log_level", cfg.Log.Level)
+
+	// airport names, cities and timezones for every mapper, reloaded on SIGHUP
+	airports, err := airport.Load(cfg.Data.Airports)
+	if err != nil {
+		log.Error("failed to load airports", "error", err)
+		os.Exit(1)
+	}
 
 	// Init Service
-	garudaService := garuda.NewGarudaService("mock/garuda_indonesia_search_response.json")
-	batikAirService := batikair.NewBatikAirService("mock/batik_air_search_response.json")
-	lionAirService := lionair.NewLionAirService("mock/lion_air_search_response.json")
-	airasia := airasia.NewAirAsiaService("mock/airasia_search_response.json")
-	// We can enhance by storing this in env
-	// Change this to localhost:6379 for running in local (without docker compose)
-	// redisService := redis.NewRedisService("redis:6379", "", 0)
-	redisService := redis.NewRedisService("localhost:6379", "", 0)
-	flightService := service.NewFlightService(garudaService, batikAirService, lionAirService, airasia, redisService)
+	providers, err := newProviders(cfg.Providers, airports, log)
+	if err != nil {
+		log.Error("failed to register providers", "error", err)
+		os.Exit(1)
+	}
+
+	rates, err := fx.LoadTable(cfg.Data.FXRates)
+	if err != nil {
+		log.Error("failed to load exchange rates", "error", err)
+		os.Exit(1)
+	}
+	if !rates.Supports(strings.ToUpper(cfg.Search.DisplayCurrency)) {
+		log.Error("invalid config: no exchange rate for search.display_currency", "currency", cfg.Search.DisplayCurrency)
+		os.Exit(1)
+	}
+
+	redis.SetLogger(log)
+	redisService := redis.NewRedisService(cfg.Redis.Addr, cfg.Redis.Password, cfg.Redis.DB)
+	// in-memory LRU in front of redis, keeps searches working when redis is down
+	cache := redis.NewLayeredCache(redisService, cfg.LayeredCacheConfig())
+	flightService := service.NewFlightService(providers, cache, rates, cfg.ServiceConfig(), log)
 
 	// Init controller
-	flightController := controller.NewFlightController(flightService)
+	flightController := controller.NewFlightController(flightService, log)
+	cacheController := controller.NewCacheController(cache, log)
+	airportController := controller.NewAirportController(airports, log)
+
+	mux := http.NewServeMux()
+	flightController.RegisterRoutes(mux)
+	cacheController.RegisterRoutes(mux)
+	airportController.RegisterRoutes(mux)
+
+	server := &http.Server{
+		Addr:              cfg.Addr(),
+		Handler:           controller.WithRequestID(log, mux),
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout.Duration,
+	}
+
+	go func() {
+		log.Info("HTTP server listening", "addr", cfg.Addr())
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("HTTP server stopped", "error", err)
+			os.Exit(1)
+		}
+	}()
 
-	// mock
-	mock(flightController)
+	// same searches over gRPC for the internal booking services
+	var grpcServer *grpc.Server
+	if cfg.Server.GRPCPort != 0 {
+		lis, err := net.Listen("tcp", cfg.GRPCAddr())
+		if err != nil {
+			log.Error("failed to listen for gRPC", "error", err)
+			os.Exit(1)
+		}
+		grpcServer = grpcserver.New(flightService, log)
+		go func() {
+			log.Info("gRPC server listening", "addr", cfg.GRPCAddr())
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Error("gRPC server stopped", "error", err)
+				os.Exit(1)
+			}
+		}()
+	}
+
+	// wait for ctrl+c / docker stop, then let in-flight searches finish
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go reloadAirports(reload, airports, log)
+	<-stop
+
+	log.Info("shutting down the app")
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout.Duration)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Error("graceful shutdown failed", "error", err)
+	}
+	if grpcServer != nil {
+		stopGRPC(ctx, grpcServer)
+	}
+}
+
+// stopGRPC lets in-flight calls finish and cuts them off when ctx runs out
+func stopGRPC(ctx context.Context, server *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		server.Stop()
+	}
 }
 
-func mock(ctrl controller.FlightController) {
-	ctrl.SearchFlightData()
+// reloadAirports reads the airport data again on every signal until signals is closed.
+// An invalid file is logged and the current data stays in use.
+func reloadAirports(signals <-chan os.Signal, airports airport.Registry, log *slog.Logger) {
+	for range signals {
+		if err := airports.Reload(); err != nil {
+			log.Error("failed to reload airports", "error", err)
+			continue
+		}
+		log.Info("reloaded airports", "airports", airports.Len())
+	}
+}
+
+// newProviders builds the airlines enabled in the config, in the configured order
+func newProviders(configs []config.ProviderConfig, airports airport.Registry, log *slog.Logger) (provider.Registry, error) {
+	enabled := make([]provider.Provider, 0, len(configs))
+	for _, pc := range configs {
+		build, ok := provider.Lookup(pc.Code)
+		if !ok {
+			return nil, fmt.Errorf("unknown provider %q in config, known providers: %s", pc.Code, strings.Join(provider.Available(), ", "))
+		}
+
+		enabled = append(enabled, build(provider.Options{
+			FixtureDir: pc.Fixtures,
+			Timeout:    pc.Timeout.Duration,
+			Airports:   airports,
+			Logger:     log,
+		}))
+	}
+	return provider.NewRegistry(enabled...)
 }
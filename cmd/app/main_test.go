@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"flight-aggregator/internal/service/airport"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestReloadAirportsOnSIGHUP(t *testing.T) {
+	const (
+		cgk = `{"code": "CGK", "name": "Soekarno-Hatta International", "city": "Jakarta", "timezone": "Asia/Jakarta"}`
+		dps = `{"code": "DPS", "name": "I Gusti Ngurah Rai International", "city": "Denpasar", "timezone": "Asia/Makassar"}`
+	)
+	path := filepath.Join(t.TempDir(), "airports.json")
+	write := func(data string) {
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("[" + cgk + "]")
+	airports, err := airport.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		wantLen int
+		wantLog string
+	}{
+		{"a new file is read", "[" + cgk + "," + dps + "]", 2, "reloaded airports"},
+		{"an invalid file keeps the data", `[{"code": "DP"}]`, 2, "failed to reload airports"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			write(tt.data)
+			var logs bytes.Buffer
+			signals := make(chan os.Signal, 1)
+			signals <- syscall.SIGHUP
+			close(signals)
+
+			// returns once the signal is handled and the channel is closed
+			reloadAirports(signals, airports, slog.New(slog.NewTextHandler(&logs, nil)))
+
+			if airports.Len() != tt.wantLen {
+				t.Errorf("airports = %d, want %d", airports.Len(), tt.wantLen)
+			}
+			if !strings.Contains(logs.String(), tt.wantLog) {
+				t.Errorf("log = %q, want %q", logs.String(), tt.wantLog)
+			}
+		})
+	}
+}
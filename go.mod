@@ -2,9 +2,17 @@ module flight-aggregator
 
 go 1.25.0
 
-require github.com/redis/go-redis/v9 v9.17.3
+require (
+	github.com/redis/go-redis/v9 v9.17.3
+	google.golang.org/grpc v1.82.1
+	google.golang.org/protobuf v1.36.12
+)
 
 require (
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	golang.org/x/net v0.53.0 // indirect
+	golang.org/x/sys v0.43.0 // indirect
+	golang.org/x/text v0.36.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260414002931-afd174a4e478 // indirect
 )
@@ -1,43 +1,125 @@
 package logger
 
 import (
-	"log"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"strings"
 )
 
+// Output formats
 const (
-	Reset = "\033[0m"
-	Red   = "\033[31m"
-	Green = "\033[32m"
+	FORMAT_TEXT = "text"
+	FORMAT_JSON = "json"
 )
 
-type Logger struct {
-	infoLog  *log.Logger
-	errorLog *log.Logger
+// Options configure the logger the app creates once at startup and hands to every component
+type Options struct {
+	// Level is debug, info (default), warn or error
+	Level string
+	// Format is text (default) or json
+	Format string
+	// Output defaults to stdout
+	Output io.Writer
 }
 
-var AppLogger *Logger
+// New builds a structured logger. Every line logged with a context that carries a
+// request ID gets a request_id attribute.
+func New(opts Options) (*slog.Logger, error) {
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	out := opts.Output
+	if out == nil {
+		out = os.Stdout
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch strings.ToLower(opts.Format) {
+	case "", FORMAT_TEXT:
+		handler = slog.NewTextHandler(out, handlerOpts)
+	case FORMAT_JSON:
+		handler = slog.NewJSONHandler(out, handlerOpts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q, use %s or %s", opts.Format, FORMAT_TEXT, FORMAT_JSON)
+	}
+	return slog.New(contextHandler{handler}), nil
+}
+
+// ParseLevel reads debug, info, warn or error, an empty level is info
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return slog.LevelInfo, fmt.Errorf("unknown log level %q, use debug, info, warn or error", level)
+}
+
+type requestIDKey struct{}
 
-func Init() *Logger {
-	AppLogger = &Logger{
-		infoLog:  log.New(os.Stdout, Green+"INFO: "+Reset, log.Ldate|log.Ltime|log.Lshortfile),
-		errorLog: log.New(os.Stderr, Red+"ERROR: "+Reset, log.Ldate|log.Ltime|log.Lshortfile),
+// WithRequestID returns a copy of ctx that carries the request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, empty when there is none
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID returns a random 16 byte ID, hex encoded
+func NewRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// maxRequestIDLength bounds an ID sent by a client
+const maxRequestIDLength = 128
+
+// KeepOrNewRequestID returns the ID a client sent when it is safe to log, a new one otherwise
+func KeepOrNewRequestID(id string) string {
+	if id == "" || len(id) > maxRequestIDLength {
+		return NewRequestID()
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune("-_.:", c)) {
+			return NewRequestID()
+		}
 	}
-	return AppLogger
+	return id
 }
 
-func (l *Logger) Info(v ...interface{}) {
-	l.infoLog.Println(v...)
+// contextHandler adds the request ID of the record's context to every line
+type contextHandler struct {
+	slog.Handler
 }
 
-func (l *Logger) Error(v ...interface{}) {
-	l.errorLog.Println(append([]interface{}{Red}, append(v, Reset)...)...)
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, record)
 }
 
-func (l *Logger) Infof(format string, v ...interface{}) {
-	l.infoLog.Printf(format, v...)
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
 }
 
-func (l *Logger) Errorf(format string, v ...interface{}) {
-	l.errorLog.Printf(Red+format+Reset, v...)
-}
\ No newline at end of file
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
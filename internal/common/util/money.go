@@ -0,0 +1,74 @@
+package util
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// currencyFormat is how one currency is written in its home locale
+type currencyFormat struct {
+	symbol    string
+	decimals  int
+	thousands string
+	decimal   string
+	// space between the symbol and the amount
+	spaced bool
+}
+
+var currencyFormats = map[string]currencyFormat{
+	"IDR": {symbol: "Rp", decimals: 0, thousands: ".", decimal: ",", spaced: true},
+	"USD": {symbol: "$", decimals: 2, thousands: ",", decimal: "."},
+	"SGD": {symbol: "S$", decimals: 2, thousands: ",", decimal: "."},
+	"MYR": {symbol: "RM", decimals: 2, thousands: ",", decimal: ".", spaced: true},
+	"AUD": {symbol: "A$", decimals: 2, thousands: ",", decimal: "."},
+	"EUR": {symbol: "€", decimals: 2, thousands: ".", decimal: ","},
+	"JPY": {symbol: "¥", decimals: 0, thousands: ",", decimal: "."},
+	"THB": {symbol: "฿", decimals: 2, thousands: ",", decimal: "."},
+	"PHP": {symbol: "₱", decimals: 2, thousands: ",", decimal: "."},
+}
+
+func formatFor(currency string) currencyFormat {
+	if format, ok := currencyFormats[currency]; ok {
+		return format
+	}
+	// unknown currencies fall back to "CODE 1,234.56"
+	return currencyFormat{symbol: currency, decimals: 2, thousands: ",", decimal: ".", spaced: true}
+}
+
+// RoundMoney rounds amount to the minor unit of currency (whole rupiah, cents for USD)
+func RoundMoney(amount float64, currency string) float64 {
+	scale := math.Pow10(formatFor(currency).decimals)
+	return math.Round(amount*scale) / scale
+}
+
+// FormatMoney writes amount the way it is written in the currency's home locale,
+// e.g. "Rp 1.250.000", "$78.13", "RM 368.42"
+func FormatMoney(amount float64, currency string) string {
+	format := formatFor(currency)
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	// rounded like RoundMoney, %f alone would round halves to even
+	str := fmt.Sprintf("%.*f", format.decimals, RoundMoney(amount, currency))
+	whole, fraction, _ := strings.Cut(str, ".")
+
+	var groups []string
+	for i := len(whole); i > 0; i -= 3 {
+		groups = append([]string{whole[max(i-3, 0):i]}, groups...)
+	}
+	number := strings.Join(groups, format.thousands)
+	if fraction != "" {
+		number += format.decimal + fraction
+	}
+
+	separator := ""
+	if format.spaced {
+		separator = " "
+	}
+	return sign + format.symbol + separator + number
+}
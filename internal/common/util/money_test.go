@@ -0,0 +1,64 @@
+package util
+
+import "testing"
+
+func TestFormatMoney(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		want     string
+	}{
+		// whole rupiah, dots between thousands
+		{1250000, "IDR", "Rp 1.250.000"},
+		{0, "IDR", "Rp 0"},
+		{999, "IDR", "Rp 999"},
+		{1000, "IDR", "Rp 1.000"},
+		{1250000.5, "IDR", "Rp 1.250.001"},
+		{-1250000, "IDR", "-Rp 1.250.000"},
+
+		{78.125, "USD", "$78.13"},
+		{1234567.891, "USD", "$1,234,567.89"},
+		{0.5, "USD", "$0.50"},
+		{-78.1, "USD", "-$78.10"},
+		{368.42, "MYR", "RM 368.42"},
+		{102.3, "SGD", "S$102.30"},
+		{1234.5, "EUR", "€1.234,50"},
+		{123456, "JPY", "¥123,456"},
+		{123456.7, "JPY", "¥123,457"},
+		{97.28, "AUD", "A$97.28"},
+		{2615.5, "THB", "฿2,615.50"},
+		{4425, "PHP", "₱4,425.00"},
+
+		// unknown currencies are written with their code
+		{1234.5, "KRW", "KRW 1,234.50"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatMoney(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("FormatMoney(%v, %s) = %q, want %q", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestRoundMoney(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		want     float64
+	}{
+		{1250000.4, "IDR", 1250000},
+		{1250000.5, "IDR", 1250001},
+		{78.125, "USD", 78.13},
+		{78.124, "USD", 78.12},
+		{-78.125, "USD", -78.13},
+		{0.1 + 0.2, "USD", 0.3},
+		{123456.5, "JPY", 123457},
+		{1234.567, "KRW", 1234.57},
+	}
+
+	for _, tt := range tests {
+		if got := RoundMoney(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("RoundMoney(%v, %s) = %v, want %v", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
@@ -5,25 +5,22 @@ import (
 	"strings"
 )
 
+// ParseDurationStringToInt converts "1h 45m", "2h" or "55m" to minutes, 0 when it can not be read
 func ParseDurationStringToInt(durationStr string) int {
-	var hours, minutes int
-	fmt.Sscanf(durationStr, "%dh %dm", &hours, &minutes)
-	return (hours * 60) + minutes
-}
-
-func FormatIDR(amount float64) string {
-	str := fmt.Sprintf("%.0f", amount)
-
-	var result []string
-	length := len(str)
+	var total int
+	for _, part := range strings.Fields(durationStr) {
+		var value int
+		var unit string
+		if _, err := fmt.Sscanf(part, "%d%s", &value, &unit); err != nil {
+			continue
+		}
 
-	for i := length; i > 0; i -= 3 {
-		start := i - 3
-		if start < 0 {
-			start = 0
+		switch unit {
+		case "h":
+			total += value * 60
+		case "m":
+			total += value
 		}
-		result = append([]string{str[start:i]}, result...)
 	}
-
-	return "Rp " + strings.Join(result, ".")
+	return total
 }
@@ -0,0 +1,691 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	logger "flight-aggregator/internal/common"
+	"flight-aggregator/internal/redis"
+	"flight-aggregator/internal/service"
+	"flight-aggregator/internal/service/provider"
+	"flight-aggregator/internal/service/resilience"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultPath is read when neither -config nor CONFIG_FILE names a file. It is
+// optional, the defaults apply when it does not exist.
+const DefaultPath = "config.json"
+
+type Config struct {
+	Server     ServerConfig     `json:"server"`
+	Redis      RedisConfig      `json:"redis"`
+	Cache      CacheConfig      `json:"cache"`
+	Search     SearchConfig     `json:"search"`
+	Resilience ResilienceConfig `json:"resilience"`
+	Fares      FareRule         `json:"fares"`
+	Providers  []ProviderConfig `json:"providers"`
+	Data       DataConfig       `json:"data"`
+	Log        LogConfig        `json:"log"`
+}
+
+type ServerConfig struct {
+	Port int `json:"port"`
+	// GRPCPort serves the gRPC API, 0 turns it off
+	GRPCPort          int      `json:"grpc_port"`
+	ReadHeaderTimeout Duration `json:"read_header_timeout"`
+	ShutdownTimeout   Duration `json:"shutdown_timeout"`
+}
+
+type RedisConfig struct {
+	Addr     string `json:"addr"`
+	Password string `json:"password"`
+	DB       int    `json:"db"`
+	// Timeout bounds every Redis call of a search
+	Timeout Duration `json:"timeout"`
+}
+
+type CacheTTL struct {
+	Fresh Duration `json:"fresh"`
+	Stale Duration `json:"stale"`
+}
+
+type CacheConfig struct {
+	CacheTTL
+	// Memory is the in-process LRU in front of Redis
+	Memory MemoryCacheConfig `json:"memory"`
+}
+
+type MemoryCacheConfig struct {
+	Capacity   int      `json:"capacity"`
+	TTL        Duration `json:"ttl"`
+	RetryAfter Duration `json:"retry_after"`
+}
+
+type SearchConfig struct {
+	// ProviderBudget bounds all attempts (retries and hedges) of one provider in one search
+	ProviderBudget Duration `json:"provider_budget"`
+	// Deadline is how long a search waits for providers before answering with what it has, 0 waits for all
+	Deadline        Duration        `json:"deadline"`
+	DisplayCurrency string          `json:"display_currency"`
+	BestValue       BestValueConfig `json:"best_value"`
+}
+
+// BestValueConfig are the weights of the best value score, in rupiah
+type BestValueConfig struct {
+	TimePerMinute float64 `json:"time_per_minute"`
+	StopPenalty   float64 `json:"stop_penalty"`
+	AmenityBonus  float64 `json:"amenity_bonus"`
+}
+
+// ResilienceConfig applies to every provider, each provider keeps its own breaker and latency record
+type ResilienceConfig struct {
+	Breaker BreakerConfig `json:"breaker"`
+	Retry   RetryConfig   `json:"retry"`
+	Hedge   HedgeConfig   `json:"hedge"`
+}
+
+type BreakerConfig struct {
+	FailureThreshold  int      `json:"failure_threshold"`
+	Cooldown          Duration `json:"cooldown"`
+	HalfOpenSuccesses int      `json:"half_open_successes"`
+}
+
+type RetryConfig struct {
+	// MaxAttempts includes the first call, 1 disables retries
+	MaxAttempts int      `json:"max_attempts"`
+	BaseDelay   Duration `json:"base_delay"`
+	MaxDelay    Duration `json:"max_delay"`
+}
+
+type HedgeConfig struct {
+	Enabled    bool `json:"enabled"`
+	MinSamples int  `json:"min_samples"`
+	// Percentile of the provider's latency after which the second call is fired
+	Percentile float64  `json:"percentile"`
+	MinDelay   Duration `json:"min_delay"`
+}
+
+// FareRule prices children and infants as a fraction of the adult fare
+type FareRule struct {
+	Child  float64 `json:"child"`
+	Infant float64 `json:"infant"`
+}
+
+// ProviderConfig enables one airline. The order of the list is the search order.
+type ProviderConfig struct {
+	Code     string   `json:"code"`
+	Fixtures string   `json:"fixtures"`
+	Timeout  Duration `json:"timeout"`
+	// Cache overrides cache.fresh and cache.stale for this airline
+	Cache *CacheTTL `json:"cache,omitempty"`
+	// Fares overrides the top-level fares for this airline
+	Fares *FareRule `json:"fares,omitempty"`
+}
+
+type DataConfig struct {
+	Airports string `json:"airports"`
+	FXRates  string `json:"fx_rates"`
+}
+
+type LogConfig struct {
+	// Level is debug, info, warn or error
+	Level string `json:"level"`
+	// Format is text or json
+	Format string `json:"format"`
+}
+
+// Default takes the tuning of the service, the resilience policies and the layered
+// cache from their own defaults, so each value is set in one place. It enables every
+// airline registered with the provider package.
+func Default() Config {
+	svc := service.DefaultConfig()
+	layered := redis.DefaultLayeredConfig()
+
+	// every registered airline, the app imports them
+	codes := provider.Available()
+	providers := make([]ProviderConfig, 0, len(codes))
+	for _, code := range codes {
+		p := ProviderConfig{
+			Code:     code,
+			Fixtures: "mock/" + strings.ToLower(code),
+			Timeout:  Duration{provider.DefaultTimeout},
+		}
+		if rule, ok := svc.Fares.Providers[code]; ok {
+			fares := fareRuleFrom(rule)
+			p.Fares = &fares
+		}
+		providers = append(providers, p)
+	}
+
+	return Config{
+		Server: ServerConfig{
+			Port:              8080,
+			GRPCPort:          9090,
+			ReadHeaderTimeout: Duration{5 * time.Second},
+			ShutdownTimeout:   Duration{10 * time.Second},
+		},
+		Redis: RedisConfig{Addr: "localhost:6379", Timeout: Duration{layered.Timeout}},
+		Cache: CacheConfig{
+			CacheTTL: cacheTTLFrom(svc.Cache.Default),
+			Memory: MemoryCacheConfig{
+				Capacity:   layered.Capacity,
+				TTL:        Duration{layered.TTL},
+				RetryAfter: Duration{layered.RetryAfter},
+			},
+		},
+		Search: SearchConfig{
+			ProviderBudget:  Duration{svc.ProviderBudget},
+			Deadline:        Duration{svc.SearchDeadline},
+			DisplayCurrency: svc.DisplayCurrency,
+			BestValue: BestValueConfig{
+				TimePerMinute: svc.BestValue.TimePerMinute,
+				StopPenalty:   svc.BestValue.StopPenalty,
+				AmenityBonus:  svc.BestValue.AmenityBonus,
+			},
+		},
+		Resilience: ResilienceConfig{
+			Breaker: breakerFrom(resilience.DefaultBreakerConfig()),
+			Retry:   retryFrom(resilience.DefaultRetryConfig()),
+			Hedge:   hedgeFrom(resilience.DefaultHedgeConfig()),
+		},
+		Fares:     fareRuleFrom(svc.Fares.Default),
+		Providers: providers,
+		Data: DataConfig{
+			Airports: "data/airports.json",
+			FXRates:  "data/fx_rates.json",
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: logger.FORMAT_TEXT,
+		},
+	}
+}
+
+// LoggerOptions builds the app logger from the log section
+func (c Config) LoggerOptions() logger.Options {
+	return logger.Options{
+		Level:  c.Log.Level,
+		Format: c.Log.Format,
+	}
+}
+
+// Addr is the listen address of the HTTP server
+func (c Config) Addr() string {
+	return fmt.Sprintf(":%d", c.Server.Port)
+}
+
+// GRPCAddr is the listen address of the gRPC server
+func (c Config) GRPCAddr() string {
+	return fmt.Sprintf(":%d", c.Server.GRPCPort)
+}
+
+// ServiceConfig is the flight service config
+func (c Config) ServiceConfig() service.Config {
+	cfg := service.DefaultConfig()
+	cfg.Breaker = c.Resilience.Breaker.toResilience()
+	cfg.Retry = c.Resilience.Retry.toResilience()
+	cfg.Hedge = c.Resilience.Hedge.toResilience()
+	cfg.ProviderBudget = c.Search.ProviderBudget.Duration
+	cfg.SearchDeadline = c.Search.Deadline.Duration
+	cfg.DisplayCurrency = strings.ToUpper(c.Search.DisplayCurrency)
+	cfg.BestValue = service.BestValueWeights{
+		TimePerMinute: c.Search.BestValue.TimePerMinute,
+		StopPenalty:   c.Search.BestValue.StopPenalty,
+		AmenityBonus:  c.Search.BestValue.AmenityBonus,
+	}
+
+	cfg.Cache = service.CacheConfig{
+		Default:   c.Cache.CacheTTL.toService(),
+		Providers: make(map[string]service.CacheTTL),
+	}
+	cfg.Fares = service.FareConfig{
+		Default:   c.Fares.toService(),
+		Providers: make(map[string]service.FareRule),
+	}
+	for _, p := range c.Providers {
+		if p.Cache != nil {
+			cfg.Cache.Providers[p.Code] = p.Cache.toService()
+		}
+		if p.Fares != nil {
+			cfg.Fares.Providers[p.Code] = p.Fares.toService()
+		}
+	}
+	return cfg
+}
+
+func (t CacheTTL) toService() service.CacheTTL {
+	return service.CacheTTL{Fresh: t.Fresh.Duration, Stale: t.Stale.Duration}
+}
+
+func (r FareRule) toService() service.FareRule {
+	return service.FareRule{Child: r.Child, Infant: r.Infant}
+}
+
+func (b BreakerConfig) toResilience() resilience.BreakerConfig {
+	return resilience.BreakerConfig{
+		FailureThreshold:  b.FailureThreshold,
+		Cooldown:          b.Cooldown.Duration,
+		HalfOpenSuccesses: b.HalfOpenSuccesses,
+	}
+}
+
+func (r RetryConfig) toResilience() resilience.RetryConfig {
+	return resilience.RetryConfig{
+		MaxAttempts: r.MaxAttempts,
+		BaseDelay:   r.BaseDelay.Duration,
+		MaxDelay:    r.MaxDelay.Duration,
+	}
+}
+
+func (h HedgeConfig) toResilience() resilience.HedgeConfig {
+	return resilience.HedgeConfig{
+		Enabled:    h.Enabled,
+		MinSamples: h.MinSamples,
+		Percentile: h.Percentile,
+		MinDelay:   h.MinDelay.Duration,
+	}
+}
+
+// The from functions turn the defaults of the packages back into config sections
+
+func cacheTTLFrom(t service.CacheTTL) CacheTTL {
+	return CacheTTL{Fresh: Duration{t.Fresh}, Stale: Duration{t.Stale}}
+}
+
+func fareRuleFrom(r service.FareRule) FareRule {
+	return FareRule{Child: r.Child, Infant: r.Infant}
+}
+
+func breakerFrom(b resilience.BreakerConfig) BreakerConfig {
+	return BreakerConfig{
+		FailureThreshold:  b.FailureThreshold,
+		Cooldown:          Duration{b.Cooldown},
+		HalfOpenSuccesses: b.HalfOpenSuccesses,
+	}
+}
+
+func retryFrom(r resilience.RetryConfig) RetryConfig {
+	return RetryConfig{
+		MaxAttempts: r.MaxAttempts,
+		BaseDelay:   Duration{r.BaseDelay},
+		MaxDelay:    Duration{r.MaxDelay},
+	}
+}
+
+func hedgeFrom(h resilience.HedgeConfig) HedgeConfig {
+	return HedgeConfig{
+		Enabled:    h.Enabled,
+		MinSamples: h.MinSamples,
+		Percentile: h.Percentile,
+		MinDelay:   Duration{h.MinDelay},
+	}
+}
+
+func (c Config) LayeredCacheConfig() redis.LayeredConfig {
+	return redis.LayeredConfig{
+		Capacity:   c.Cache.Memory.Capacity,
+		TTL:        c.Cache.Memory.TTL.Duration,
+		RetryAfter: c.Cache.Memory.RetryAfter.Duration,
+		Timeout:    c.Redis.Timeout.Duration,
+	}
+}
+
+// Load builds the config from, in increasing priority, the defaults, the config
+// file, the environment and the command-line flags in args, then validates it.
+func Load(args []string) (Config, error) {
+	cfg := Default()
+
+	fs := flag.NewFlagSet("flight-aggregator", flag.ContinueOnError)
+	path := fs.String("config", "", "config file (default $CONFIG_FILE or "+DefaultPath+")")
+	port := fs.Int("port", 0, "HTTP port")
+	grpcPort := fs.Int("grpc-port", 0, "gRPC port, 0 turns the gRPC API off")
+	redisAddr := fs.String("redis-addr", "", "Redis host:port")
+	redisDB := fs.Int("redis-db", 0, "Redis database")
+	providerTimeout := fs.Duration("provider-timeout", 0, "timeout of a single call to every provider")
+	providerBudget := fs.Duration("provider-budget", 0, "time budget of one provider in one search, retries included")
+	searchDeadline := fs.Duration("search-deadline", 0, "how long a search waits for providers, 0 waits for all")
+	breakerThreshold := fs.Int("breaker-threshold", 0, "consecutive failures that open a provider's circuit breaker")
+	breakerCooldown := fs.Duration("breaker-cooldown", 0, "how long an open breaker rejects calls")
+	retryAttempts := fs.Int("retry-attempts", 0, "attempts per provider call, the first one included")
+	retryBaseDelay := fs.Duration("retry-base-delay", 0, "backoff before the first retry, doubled on every retry")
+	retryMaxDelay := fs.Duration("retry-max-delay", 0, "longest backoff between retries")
+	hedgeMinSamples := fs.Int("hedge-min-samples", 0, "calls recorded before a provider is hedged")
+	hedgePercentile := fs.Float64("hedge-percentile", 0, "latency percentile after which a hedged call is fired")
+	displayCurrency := fs.String("display-currency", "", "default display currency")
+	logLevel := fs.String("log-level", "", "debug, info, warn or error")
+	logFormat := fs.String("log-format", "", "text or json")
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	file, explicit := *path, *path != ""
+	if !explicit {
+		file, explicit = os.LookupEnv("CONFIG_FILE")
+	}
+	if file == "" {
+		file = DefaultPath
+	}
+	if err := cfg.readFile(file, explicit); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return Config{}, err
+	}
+
+	// only the flags given on the command line override
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "port":
+			cfg.Server.Port = *port
+		case "grpc-port":
+			cfg.Server.GRPCPort = *grpcPort
+		case "redis-addr":
+			cfg.Redis.Addr = *redisAddr
+		case "redis-db":
+			cfg.Redis.DB = *redisDB
+		case "provider-timeout":
+			cfg.setProviderTimeout(*providerTimeout)
+		case "provider-budget":
+			cfg.Search.ProviderBudget = Duration{*providerBudget}
+		case "search-deadline":
+			cfg.Search.Deadline = Duration{*searchDeadline}
+		case "breaker-threshold":
+			cfg.Resilience.Breaker.FailureThreshold = *breakerThreshold
+		case "breaker-cooldown":
+			cfg.Resilience.Breaker.Cooldown = Duration{*breakerCooldown}
+		case "retry-attempts":
+			cfg.Resilience.Retry.MaxAttempts = *retryAttempts
+		case "retry-base-delay":
+			cfg.Resilience.Retry.BaseDelay = Duration{*retryBaseDelay}
+		case "retry-max-delay":
+			cfg.Resilience.Retry.MaxDelay = Duration{*retryMaxDelay}
+		case "hedge-min-samples":
+			cfg.Resilience.Hedge.MinSamples = *hedgeMinSamples
+		case "hedge-percentile":
+			cfg.Resilience.Hedge.Percentile = *hedgePercentile
+		case "display-currency":
+			cfg.Search.DisplayCurrency = *displayCurrency
+		case "log-level":
+			cfg.Log.Level = *logLevel
+		case "log-format":
+			cfg.Log.Format = *logFormat
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// readFile merges the file over the defaults. A missing file is only an error
+// when it was asked for.
+func (c *Config) readFile(path string, required bool) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read config %s: %w", path, err)
+	}
+
+	// a providers list in the file replaces the default one instead of merging into it
+	defaults := c.Providers
+	c.Providers = nil
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(c); err != nil {
+		return fmt.Errorf("parse config %s: %w", path, err)
+	}
+
+	if c.Providers == nil {
+		c.Providers = defaults
+	}
+	for i := range c.Providers {
+		if c.Providers[i].Timeout.Duration == 0 {
+			c.Providers[i].Timeout = Duration{provider.DefaultTimeout}
+		}
+	}
+	return nil
+}
+
+// applyEnv reads the variables docker-compose and the deployment set
+func (c *Config) applyEnv() error {
+	var problems []string
+	envString := func(name string, target *string) {
+		if value, ok := os.LookupEnv(name); ok {
+			*target = value
+		}
+	}
+	envInt := func(name string, target *int) {
+		if value, ok := os.LookupEnv(name); ok {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s=%q is not a number", name, value))
+				return
+			}
+			*target = parsed
+		}
+	}
+	envFloat := func(name string, target *float64) {
+		if value, ok := os.LookupEnv(name); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s=%q is not a number", name, value))
+				return
+			}
+			*target = parsed
+		}
+	}
+	envBool := func(name string, target *bool) {
+		if value, ok := os.LookupEnv(name); ok {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s=%q is not true or false", name, value))
+				return
+			}
+			*target = parsed
+		}
+	}
+	envDuration := func(name string, set func(time.Duration)) {
+		if value, ok := os.LookupEnv(name); ok {
+			parsed, err := time.ParseDuration(value)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s=%q is not a duration like 2s", name, value))
+				return
+			}
+			set(parsed)
+		}
+	}
+
+	envInt("SERVER_PORT", &c.Server.Port)
+	envInt("GRPC_PORT", &c.Server.GRPCPort)
+	envString("REDIS_ADDR", &c.Redis.Addr)
+	envString("REDIS_PASSWORD", &c.Redis.Password)
+	envInt("REDIS_DB", &c.Redis.DB)
+	envDuration("REDIS_TIMEOUT", func(d time.Duration) { c.Redis.Timeout = Duration{d} })
+	envDuration("CACHE_FRESH_TTL", func(d time.Duration) { c.Cache.Fresh = Duration{d} })
+	envDuration("CACHE_STALE_TTL", func(d time.Duration) { c.Cache.Stale = Duration{d} })
+	envDuration("PROVIDER_TIMEOUT", c.setProviderTimeout)
+	envDuration("PROVIDER_BUDGET", func(d time.Duration) { c.Search.ProviderBudget = Duration{d} })
+	envDuration("SEARCH_DEADLINE", func(d time.Duration) { c.Search.Deadline = Duration{d} })
+	envString("DISPLAY_CURRENCY", &c.Search.DisplayCurrency)
+	envInt("BREAKER_FAILURE_THRESHOLD", &c.Resilience.Breaker.FailureThreshold)
+	envDuration("BREAKER_COOLDOWN", func(d time.Duration) { c.Resilience.Breaker.Cooldown = Duration{d} })
+	envInt("BREAKER_HALF_OPEN_SUCCESSES", &c.Resilience.Breaker.HalfOpenSuccesses)
+	envInt("RETRY_MAX_ATTEMPTS", &c.Resilience.Retry.MaxAttempts)
+	envDuration("RETRY_BASE_DELAY", func(d time.Duration) { c.Resilience.Retry.BaseDelay = Duration{d} })
+	envDuration("RETRY_MAX_DELAY", func(d time.Duration) { c.Resilience.Retry.MaxDelay = Duration{d} })
+	envBool("HEDGE_ENABLED", &c.Resilience.Hedge.Enabled)
+	envInt("HEDGE_MIN_SAMPLES", &c.Resilience.Hedge.MinSamples)
+	envFloat("HEDGE_PERCENTILE", &c.Resilience.Hedge.Percentile)
+	envDuration("HEDGE_MIN_DELAY", func(d time.Duration) { c.Resilience.Hedge.MinDelay = Duration{d} })
+	envString("LOG_LEVEL", &c.Log.Level)
+	envString("LOG_FORMAT", &c.Log.Format)
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid environment:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+func (c *Config) setProviderTimeout(timeout time.Duration) {
+	for i := range c.Providers {
+		c.Providers[i].Timeout = Duration{timeout}
+	}
+}
+
+// Validate reports every problem at once, so a broken deployment is fixed in one go
+func (c Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		add("server.port must be between 1 and 65535, got %d", c.Server.Port)
+	}
+	if c.Server.GRPCPort < 0 || c.Server.GRPCPort > 65535 {
+		add("server.grpc_port must be between 0 and 65535, got %d", c.Server.GRPCPort)
+	}
+	if c.Server.GRPCPort == c.Server.Port {
+		add("server.grpc_port and server.port cannot both be %d", c.Server.Port)
+	}
+	if c.Server.ReadHeaderTimeout.Duration <= 0 {
+		add("server.read_header_timeout must be greater than zero")
+	}
+	if c.Server.ShutdownTimeout.Duration <= 0 {
+		add("server.shutdown_timeout must be greater than zero")
+	}
+
+	if _, _, err := net.SplitHostPort(c.Redis.Addr); err != nil {
+		add("redis.addr must be host:port, got %q", c.Redis.Addr)
+	}
+	if c.Redis.DB < 0 {
+		add("redis.db cannot be negative")
+	}
+	if c.Redis.Timeout.Duration <= 0 {
+		add("redis.timeout must be greater than zero")
+	}
+
+	validateTTL := func(name string, ttl CacheTTL) {
+		if ttl.Fresh.Duration <= 0 {
+			add("%s.fresh must be greater than zero", name)
+		}
+		if ttl.Stale.Duration < 0 {
+			add("%s.stale cannot be negative", name)
+		}
+	}
+	validateTTL("cache", c.Cache.CacheTTL)
+	if c.Cache.Memory.Capacity <= 0 {
+		add("cache.memory.capacity must be greater than zero")
+	}
+	if c.Cache.Memory.TTL.Duration <= 0 || c.Cache.Memory.RetryAfter.Duration <= 0 {
+		add("cache.memory.ttl and cache.memory.retry_after must be greater than zero")
+	}
+
+	if c.Search.ProviderBudget.Duration <= 0 {
+		add("search.provider_budget must be greater than zero")
+	}
+	if c.Search.Deadline.Duration < 0 {
+		add("search.deadline cannot be negative")
+	}
+	if len(c.Search.DisplayCurrency) != 3 {
+		add("search.display_currency must be a 3-letter ISO code, got %q", c.Search.DisplayCurrency)
+	}
+	weights := c.Search.BestValue
+	if weights.TimePerMinute < 0 || weights.StopPenalty < 0 || weights.AmenityBonus < 0 {
+		add("search.best_value weights cannot be negative")
+	}
+
+	breaker, retry, hedge := c.Resilience.Breaker, c.Resilience.Retry, c.Resilience.Hedge
+	if breaker.FailureThreshold < 1 {
+		add("resilience.breaker.failure_threshold must be at least 1, got %d", breaker.FailureThreshold)
+	}
+	if breaker.Cooldown.Duration <= 0 {
+		add("resilience.breaker.cooldown must be greater than zero")
+	}
+	if breaker.HalfOpenSuccesses < 1 {
+		add("resilience.breaker.half_open_successes must be at least 1, got %d", breaker.HalfOpenSuccesses)
+	}
+	if retry.MaxAttempts < 1 {
+		add("resilience.retry.max_attempts must be at least 1, got %d", retry.MaxAttempts)
+	}
+	if retry.BaseDelay.Duration <= 0 || retry.MaxDelay.Duration <= 0 {
+		add("resilience.retry.base_delay and resilience.retry.max_delay must be greater than zero")
+	} else if retry.MaxDelay.Duration < retry.BaseDelay.Duration {
+		add("resilience.retry.max_delay cannot be shorter than resilience.retry.base_delay")
+	}
+	if hedge.MinSamples < 1 {
+		add("resilience.hedge.min_samples must be at least 1, got %d", hedge.MinSamples)
+	}
+	if hedge.Percentile <= 0 || hedge.Percentile > 100 {
+		add("resilience.hedge.percentile must be above 0 and at most 100, got %v", hedge.Percentile)
+	}
+	if hedge.MinDelay.Duration < 0 {
+		add("resilience.hedge.min_delay cannot be negative")
+	}
+
+	validateFares := func(name string, rule FareRule) {
+		if rule.Child < 0 || rule.Child > 1 || rule.Infant < 0 || rule.Infant > 1 {
+			add("%s.child and %s.infant must be between 0 and 1", name, name)
+		}
+	}
+	validateFares("fares", c.Fares)
+
+	if len(c.Providers) == 0 {
+		add("providers must enable at least one airline")
+	}
+	seen := make(map[string]bool)
+	for i, p := range c.Providers {
+		name := fmt.Sprintf("providers[%d]", i)
+		if p.Code == "" {
+			add("%s.code is required", name)
+		} else {
+			name = fmt.Sprintf("providers[%s]", p.Code)
+		}
+		if seen[p.Code] {
+			add("%s is listed twice", name)
+		}
+		seen[p.Code] = true
+
+		if info, err := os.Stat(p.Fixtures); err != nil || !info.IsDir() {
+			add("%s.fixtures %q is not a directory", name, p.Fixtures)
+		}
+		if p.Timeout.Duration <= 0 {
+			add("%s.timeout must be greater than zero", name)
+		}
+		if p.Cache != nil {
+			validateTTL(name+".cache", *p.Cache)
+		}
+		if p.Fares != nil {
+			validateFares(name+".fares", *p.Fares)
+		}
+	}
+
+	for name, path := range map[string]string{"data.airports": c.Data.Airports, "data.fx_rates": c.Data.FXRates} {
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			add("%s %q is not a readable file", name, path)
+		}
+	}
+
+	if _, err := logger.ParseLevel(c.Log.Level); err != nil {
+		add("log.level: %v", err)
+	}
+	if format := strings.ToLower(c.Log.Format); format != logger.FORMAT_TEXT && format != logger.FORMAT_JSON {
+		add("log.format must be %s or %s, got %q", logger.FORMAT_TEXT, logger.FORMAT_JSON, c.Log.Format)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid config:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
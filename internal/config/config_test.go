@@ -0,0 +1,364 @@
+package config
+
+import (
+	"flight-aggregator/internal/redis"
+	"flight-aggregator/internal/service"
+	"flight-aggregator/internal/service/provider"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	// the airlines register themselves, Default enables them
+	_ "flight-aggregator/internal/service/airasia"
+	_ "flight-aggregator/internal/service/batikair"
+	_ "flight-aggregator/internal/service/garuda"
+	_ "flight-aggregator/internal/service/lionair"
+)
+
+// The defaults live in the service, resilience and redis packages, Default only
+// mirrors them and has to hand them back unchanged.
+func TestDefaultMirrorsThePackageDefaults(t *testing.T) {
+	got := Default().ServiceConfig()
+	want := service.DefaultConfig()
+
+	if got.Breaker != want.Breaker || got.Retry != want.Retry || got.Hedge != want.Hedge {
+		t.Errorf("resilience = %+v %+v %+v, want %+v %+v %+v", got.Breaker, got.Retry, got.Hedge, want.Breaker, want.Retry, want.Hedge)
+	}
+	if got.ProviderBudget != want.ProviderBudget || got.SearchDeadline != want.SearchDeadline {
+		t.Errorf("budget, deadline = %s, %s, want %s, %s", got.ProviderBudget, got.SearchDeadline, want.ProviderBudget, want.SearchDeadline)
+	}
+	if got.DisplayCurrency != want.DisplayCurrency || got.BestValue != want.BestValue {
+		t.Errorf("currency, best value = %s %+v, want %s %+v", got.DisplayCurrency, got.BestValue, want.DisplayCurrency, want.BestValue)
+	}
+	if got.Cache.Default != want.Cache.Default || len(got.Cache.Providers) != 0 {
+		t.Errorf("cache = %+v, want %+v", got.Cache, want.Cache)
+	}
+	if got.Fares.Default != want.Fares.Default || !maps.Equal(got.Fares.Providers, want.Fares.Providers) {
+		t.Errorf("fares = %+v, want %+v", got.Fares, want.Fares)
+	}
+
+	if layered := Default().LayeredCacheConfig(); layered != redis.DefaultLayeredConfig() {
+		t.Errorf("layered cache = %+v, want %+v", layered, redis.DefaultLayeredConfig())
+	}
+}
+
+func TestDefaultEnablesEveryRegisteredAirline(t *testing.T) {
+	var codes, fixtures []string
+	for _, p := range Default().Providers {
+		codes = append(codes, p.Code)
+		fixtures = append(fixtures, p.Fixtures)
+	}
+
+	if want := []string{"AirAsia", "BatikAir", "Garuda", "LionAir"}; !slices.Equal(codes, want) {
+		t.Errorf("providers = %v, want %v", codes, want)
+	}
+	if want := []string{"mock/airasia", "mock/batikair", "mock/garuda", "mock/lionair"}; !slices.Equal(fixtures, want) {
+		t.Errorf("fixtures = %v, want %v", fixtures, want)
+	}
+}
+
+// inTempDir runs the test from an empty directory that has the data files and
+// fixture directories the defaults point at, and no config.json
+func inTempDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, p := range Default().Providers {
+		if err := os.MkdirAll(filepath.Join(dir, p.Fixtures), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "data"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"airports.json", "fx_rates.json"} {
+		if err := os.WriteFile(filepath.Join(dir, "data", name), []byte("[]"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(dir)
+	return dir
+}
+
+func writeConfig(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	const file = `{"server": {"port": 9000}, "log": {"level": "debug"}, "search": {"deadline": "1s"}}`
+
+	tests := []struct {
+		name         string
+		file         string
+		env          map[string]string
+		args         []string
+		wantPort     int
+		wantLevel    string
+		wantDeadline time.Duration
+		wantRedis    string
+	}{
+		{
+			name:     "defaults",
+			wantPort: 8080, wantLevel: "info", wantDeadline: Default().Search.Deadline.Duration, wantRedis: "localhost:6379",
+		},
+		{
+			name:     "the file over the defaults",
+			file:     file,
+			wantPort: 9000, wantLevel: "debug", wantDeadline: time.Second, wantRedis: "localhost:6379",
+		},
+		{
+			name:     "the environment over the file",
+			file:     file,
+			env:      map[string]string{"SERVER_PORT": "9100", "REDIS_ADDR": "redis:6379"},
+			wantPort: 9100, wantLevel: "debug", wantDeadline: time.Second, wantRedis: "redis:6379",
+		},
+		{
+			name:     "flags over the environment",
+			file:     file,
+			env:      map[string]string{"SERVER_PORT": "9100", "LOG_LEVEL": "warn"},
+			args:     []string{"-port", "9200", "-search-deadline", "3s"},
+			wantPort: 9200, wantLevel: "warn", wantDeadline: 3 * time.Second, wantRedis: "localhost:6379",
+		},
+		{
+			name:     "a flag set to its zero value still overrides",
+			file:     file,
+			args:     []string{"-search-deadline", "0s"},
+			wantPort: 9000, wantLevel: "debug", wantDeadline: 0, wantRedis: "localhost:6379",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := inTempDir(t)
+			args := tt.args
+			if tt.file != "" {
+				path := filepath.Join(dir, "test.json")
+				writeConfig(t, path, tt.file)
+				args = append([]string{"-config", path}, args...)
+			}
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			cfg, err := Load(args)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.Server.Port != tt.wantPort || cfg.Log.Level != tt.wantLevel ||
+				cfg.Search.Deadline.Duration != tt.wantDeadline || cfg.Redis.Addr != tt.wantRedis {
+				t.Errorf("port, level, deadline, redis = %d, %s, %s, %s, want %d, %s, %s, %s",
+					cfg.Server.Port, cfg.Log.Level, cfg.Search.Deadline, cfg.Redis.Addr,
+					tt.wantPort, tt.wantLevel, tt.wantDeadline, tt.wantRedis)
+			}
+			// what no layer sets keeps its default
+			if cfg.Server.GRPCPort != 9090 || len(cfg.Providers) != len(Default().Providers) {
+				t.Errorf("grpc port %d and %d providers, want the defaults", cfg.Server.GRPCPort, len(cfg.Providers))
+			}
+		})
+	}
+}
+
+func TestLoadFileSources(t *testing.T) {
+	dir := inTempDir(t)
+	writeConfig(t, filepath.Join(dir, DefaultPath), `{"server": {"port": 9000}}`)
+	writeConfig(t, filepath.Join(dir, "env.json"), `{"server": {"port": 9100}}`)
+	writeConfig(t, filepath.Join(dir, "flag.json"), `{"server": {"port": 9200}}`)
+
+	tests := []struct {
+		name       string
+		configFile string
+		args       []string
+		want       int
+	}{
+		{"config.json when nothing names a file", "", nil, 9000},
+		{"CONFIG_FILE over config.json", "env.json", nil, 9100},
+		{"-config over CONFIG_FILE", "env.json", []string{"-config", "flag.json"}, 9200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.configFile != "" {
+				t.Setenv("CONFIG_FILE", tt.configFile)
+			}
+			cfg, err := Load(tt.args)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.Server.Port != tt.want {
+				t.Errorf("port = %d, want %d", cfg.Server.Port, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadProviders(t *testing.T) {
+	dir := inTempDir(t)
+	if err := os.MkdirAll(filepath.Join(dir, "fixtures"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "test.json")
+	// a providers list replaces the default one, a provider without a timeout gets the default
+	writeConfig(t, path, `{"providers": [{"code": "Garuda", "fixtures": "fixtures"}, {"code": "LionAir", "fixtures": "fixtures", "timeout": "5s"}]}`)
+
+	cfg, err := Load([]string{"-config", path})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	var got []string
+	for _, p := range cfg.Providers {
+		got = append(got, fmt.Sprintf("%s %s", p.Code, p.Timeout))
+	}
+	if want := []string{"Garuda " + provider.DefaultTimeout.String(), "LionAir 5s"}; !slices.Equal(got, want) {
+		t.Errorf("providers = %v, want %v", got, want)
+	}
+
+	// -provider-timeout applies to every provider
+	cfg, err = Load([]string{"-config", path, "-provider-timeout", "700ms"})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	for _, p := range cfg.Providers {
+		if p.Timeout.Duration != 700*time.Millisecond {
+			t.Errorf("%s timeout = %s, want 700ms", p.Code, p.Timeout)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		env     map[string]string
+		args    []string
+		wantErr []string
+	}{
+		{name: "an unknown flag", args: []string{"-nope"}, wantErr: []string{"flag provided but not defined"}},
+		{name: "a missing file that was asked for", args: []string{"-config", "missing.json"}, wantErr: []string{"read config missing.json"}},
+		{name: "an unknown field", file: `{"server": {"prot": 9000}}`, wantErr: []string{`unknown field "prot"`}},
+		{name: "a duration that is not a string", file: `{"search": {"deadline": 5}}`, wantErr: []string{`a string like "2s"`}},
+		{
+			name:    "every bad variable is reported",
+			env:     map[string]string{"SERVER_PORT": "http", "HEDGE_ENABLED": "maybe", "SEARCH_DEADLINE": "5"},
+			wantErr: []string{`SERVER_PORT="http" is not a number`, `HEDGE_ENABLED="maybe" is not true or false`, `SEARCH_DEADLINE="5" is not a duration`},
+		},
+		{
+			name:    "the result is validated",
+			args:    []string{"-port", "9090", "-log-format", "xml"},
+			wantErr: []string{"server.grpc_port and server.port cannot both be 9090", `log.format must be text or json, got "xml"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := inTempDir(t)
+			args := tt.args
+			if tt.file != "" {
+				path := filepath.Join(dir, "test.json")
+				writeConfig(t, path, tt.file)
+				args = append([]string{"-config", path}, args...)
+			}
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			_, err := Load(args)
+			if err == nil {
+				t.Fatal("Load succeeded")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Load error = %v, want it to mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(c *Config)
+		wantErr []string
+	}{
+		{"the defaults", func(c *Config) {}, nil},
+		{
+			name: "every problem is reported at once",
+			change: func(c *Config) {
+				c.Server.Port = 0
+				c.Redis.Addr = "localhost"
+				c.Search.DisplayCurrency = "RUPIAH"
+				c.Resilience.Retry.BaseDelay, c.Resilience.Retry.MaxDelay = Duration{time.Second}, Duration{time.Millisecond}
+			},
+			wantErr: []string{
+				"server.port must be between 1 and 65535, got 0",
+				`redis.addr must be host:port, got "localhost"`,
+				`search.display_currency must be a 3-letter ISO code, got "RUPIAH"`,
+				"resilience.retry.max_delay cannot be shorter than resilience.retry.base_delay",
+			},
+		},
+		{
+			name: "provider problems name the provider",
+			change: func(c *Config) {
+				c.Providers[0].Fixtures = "nowhere"
+				c.Providers[1].Cache = &CacheTTL{}
+				c.Providers[2].Fares = &FareRule{Child: 1.5}
+				c.Providers[3].Code = c.Providers[0].Code
+			},
+			wantErr: []string{
+				`providers[AirAsia].fixtures "nowhere" is not a directory`,
+				"providers[BatikAir].cache.fresh must be greater than zero",
+				"providers[Garuda].fares.child and providers[Garuda].fares.infant must be between 0 and 1",
+				"providers[AirAsia] is listed twice",
+			},
+		},
+		{
+			name:    "no provider",
+			change:  func(c *Config) { c.Providers = nil },
+			wantErr: []string{"providers must enable at least one airline"},
+		},
+		{
+			name:    "missing data files",
+			change:  func(c *Config) { c.Data.Airports = "data" },
+			wantErr: []string{`data.airports "data" is not a readable file`},
+		},
+		{
+			name:    "the log level",
+			change:  func(c *Config) { c.Log.Level = "loud" },
+			wantErr: []string{"log.level"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inTempDir(t)
+			cfg := Default()
+			tt.change(&cfg)
+
+			err := cfg.Validate()
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("Validate = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate found nothing")
+			}
+			if got := strings.Count(err.Error(), "\n  - "); got != len(tt.wantErr) {
+				t.Errorf("Validate reported %d problems, want %d: %v", got, len(tt.wantErr), err)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate = %v, want it to mention %q", err, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"encoding/json"
+	"flight-aggregator/internal/entity"
+	"flight-aggregator/internal/service/airport"
+	"log/slog"
+	"net/http"
+)
+
+type AirportController struct {
+	airports airport.Registry
+	logger   *slog.Logger
+}
+
+func NewAirportController(airports airport.Registry, logger *slog.Logger) AirportController {
+	return AirportController{
+		airports: airports,
+		logger:   logger,
+	}
+}
+
+func (a *AirportController) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v1/airports", a.FindByCity)
+	mux.HandleFunc("GET /v1/airports/{code}", a.Get)
+}
+
+// Get handles GET /v1/airports/{code}
+func (a *AirportController) Get(w http.ResponseWriter, r *http.Request) {
+	found, ok := a.airports.Get(r.PathValue("code"))
+	if !ok {
+		a.writeJSON(w, http.StatusNotFound, entity.ErrorResponse{Error: "airport not found"})
+		return
+	}
+	a.writeJSON(w, http.StatusOK, found)
+}
+
+// FindByCity handles GET /v1/airports?city=Jakarta
+func (a *AirportController) FindByCity(w http.ResponseWriter, r *http.Request) {
+	city := r.URL.Query().Get("city")
+	if city == "" {
+		a.writeJSON(w, http.StatusBadRequest, entity.ErrorResponse{Error: "city query parameter is required"})
+		return
+	}
+
+	airports := a.airports.FindByCity(city)
+	if airports == nil {
+		airports = []entity.Airport{}
+	}
+	a.writeJSON(w, http.StatusOK, airports)
+}
+
+func (a *AirportController) writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		a.logger.Error("failed to encode response", "error", err)
+	}
+}
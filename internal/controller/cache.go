@@ -0,0 +1,32 @@
+package controller
+
+import (
+	"encoding/json"
+	"flight-aggregator/internal/redis"
+	"log/slog"
+	"net/http"
+)
+
+type CacheController struct {
+	cache  redis.LayeredCache
+	logger *slog.Logger
+}
+
+func NewCacheController(cache redis.LayeredCache, logger *slog.Logger) CacheController {
+	return CacheController{
+		cache:  cache,
+		logger: logger,
+	}
+}
+
+func (c *CacheController) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v1/cache/stats", c.Stats)
+}
+
+// Stats handles GET /v1/cache/stats
+func (c *CacheController) Stats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(c.cache.Stats()); err != nil {
+		c.logger.ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
@@ -1,67 +1,162 @@
 package controller
 
 import (
-	"context"
 	"encoding/json"
-	logger "flight-aggregator/internal/common"
+	"errors"
 	"flight-aggregator/internal/entity"
 	"flight-aggregator/internal/service"
+	"fmt"
+	"log/slog"
+	"net/http"
 )
 
+// limit the request body so a bad client can not exhaust memory
+const maxRequestBodyBytes = 1 << 20
+
 type FlightController struct {
 	flightSerivice service.FlightService
-	logger         *logger.Logger
+	logger         *slog.Logger
 }
 
-func NewFlightController(flightService service.FlightService) FlightController {
-	// this should change to gin http handler instead of returning for mock
+func NewFlightController(flightService service.FlightService, logger *slog.Logger) FlightController {
 	return FlightController{
 		flightSerivice: flightService,
-		logger:         logger.Init(),
+		logger:         logger,
+	}
+}
+
+func (f *FlightController) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /v1/flights/search", f.SearchFlightData)
+	mux.HandleFunc("GET /v1/flights/search/stream", f.SearchFlightStream)
+	mux.HandleFunc("POST /v1/flights/search/multi-city", f.SearchMultiCity)
+	mux.HandleFunc("POST /v1/flights/calendar", f.SearchFareCalendar)
+}
+
+// SearchFlightData handles POST /v1/flights/search
+func (f *FlightController) SearchFlightData(w http.ResponseWriter, r *http.Request) {
+	f.logger.DebugContext(r.Context(), "search flights")
+
+	var req entity.SearchRequest
+	if !f.decodeBody(w, r, &req) {
+		return
+	}
+
+	result, err := f.flightSerivice.SearchFlight(r.Context(), req)
+	if err != nil {
+		f.writeSearchError(w, r, err)
+		return
 	}
+
+	f.writeJSON(w, http.StatusOK, result)
 }
 
-func (f *FlightController) SearchFlightData() {
-	f.logger.Info("Initialize SearchFlightData")
-
-	context := context.Background()
-
-	// stop := 0
-
-	// MOCK request
-	req := entity.SearchRequest{
-		Origin:        "CGK",
-		Destination:   []string{"DPS"},
-		DepartureDate: "2025-12-15",
-		Passanger:     1,
-		CabinClass:    "economy",
-		// 		Airlines
-		Airlines: []string{},
-		// 		Price range
-		// PriceMin: 400000,
-		// PriceMax: 600000,
-		// 		number of stops
-		// MaxStops: &stop,
-		// 		Duration
-		// MaxDuration: 110,
-		//		TIME
-		// MinDepTime: "03:00",
-		// MaxDepTime: "12:00",
-
-		// 			Sort By
-		// SortOrder: "ASC",
-		// SortBy:    "Price",
+// SearchFlightStream handles GET /v1/flights/search/stream. The search fields are query
+// parameters named like the JSON body, and results are sent as Server-Sent Events.
+func (f *FlightController) SearchFlightStream(w http.ResponseWriter, r *http.Request) {
+	f.logger.DebugContext(r.Context(), "stream flight search")
+
+	var req entity.SearchRequest
+	if err := decodeQuery(r.URL.Query(), &req); err != nil {
+		f.writeError(w, http.StatusBadRequest, "invalid query: "+err.Error())
+		return
 	}
 
-	result, err := f.flightSerivice.SearchFlight(context, req)
+	events, err := f.flightSerivice.SearchFlightStream(r.Context(), req)
 	if err != nil {
-		f.logger.Error(err)
+		f.writeSearchError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// keeps reverse proxies such as nginx from holding events back
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	for event := range events {
+		data, err := json.Marshal(event.Data())
+		if err != nil {
+			f.logger.ErrorContext(r.Context(), "failed to encode event", "event", event.Type, "error", err)
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+			// the client is gone, its context cancels the search
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			f.logger.ErrorContext(r.Context(), "failed to flush event", "event", event.Type, "error", err)
+			return
+		}
+	}
+}
+
+// SearchMultiCity handles POST /v1/flights/search/multi-city
+func (f *FlightController) SearchMultiCity(w http.ResponseWriter, r *http.Request) {
+	f.logger.DebugContext(r.Context(), "search multi-city")
+
+	var req entity.MultiCitySearchRequest
+	if !f.decodeBody(w, r, &req) {
+		return
 	}
 
-	byteData, err := json.MarshalIndent(result, "", "    ")
+	result, err := f.flightSerivice.SearchMultiCity(r.Context(), req)
 	if err != nil {
-		f.logger.Error("Failed to marshal:", err)
+		f.writeSearchError(w, r, err)
 		return
 	}
-	f.logger.Info("result: ", string(byteData))
+
+	f.writeJSON(w, http.StatusOK, result)
+}
+
+// SearchFareCalendar handles POST /v1/flights/calendar
+func (f *FlightController) SearchFareCalendar(w http.ResponseWriter, r *http.Request) {
+	f.logger.DebugContext(r.Context(), "search fare calendar")
+
+	var req entity.FareCalendarRequest
+	if !f.decodeBody(w, r, &req) {
+		return
+	}
+
+	result, err := f.flightSerivice.SearchFareCalendar(r.Context(), req)
+	if err != nil {
+		f.writeSearchError(w, r, err)
+		return
+	}
+
+	f.writeJSON(w, http.StatusOK, result)
+}
+
+// decodeBody writes a 400 and returns false when the body is not a valid target
+func (f *FlightController) decodeBody(w http.ResponseWriter, r *http.Request, target interface{}) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		f.writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// writeSearchError maps validation errors to 400 and everything else to 500
+func (f *FlightController) writeSearchError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, entity.ErrInvalidRequest) {
+		f.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	f.logger.ErrorContext(r.Context(), "search failed", "error", err)
+	f.writeError(w, http.StatusInternalServerError, "failed to search flights")
+}
+
+func (f *FlightController) writeError(w http.ResponseWriter, status int, message string) {
+	f.writeJSON(w, status, entity.ErrorResponse{Error: message})
+}
+
+func (f *FlightController) writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		f.logger.Error("failed to encode response", "error", err)
+	}
 }
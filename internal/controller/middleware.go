@@ -0,0 +1,48 @@
+package controller
+
+import (
+	logger "flight-aggregator/internal/common"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// RequestIDHeader ties every log line of one request together, the caller may send its own
+const RequestIDHeader = "X-Request-ID"
+
+// WithRequestID puts the caller's X-Request-ID, or a new one, in the request context and
+// on the response, and logs every request once it is served
+func WithRequestID(log *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := logger.KeepOrNewRequestID(r.Header.Get(RequestIDHeader))
+		w.Header().Set(RequestIDHeader, id)
+		ctx := logger.WithRequestID(r.Context(), id)
+
+		startTime := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		log.InfoContext(ctx, "request served",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", recorder.status,
+			"duration_ms", time.Since(startTime).Milliseconds(),
+		)
+	})
+}
+
+// statusRecorder remembers the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap lets http.ResponseController reach the Flusher of the real writer, streaming needs it
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
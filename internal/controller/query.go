@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// decodeQuery fills target, a pointer to a struct, from query parameters named after
+// its JSON fields. Lists take repeated parameters or comma separated values, and an
+// unknown parameter is an error like an unknown field in a JSON body.
+func decodeQuery(query url.Values, target interface{}) error {
+	fields := make(map[string]reflect.Value)
+	collectQueryFields(reflect.ValueOf(target).Elem(), fields)
+
+	for name, values := range query {
+		field, ok := fields[name]
+		if !ok {
+			return fmt.Errorf("unknown parameter %q", name)
+		}
+		if err := setQueryField(field, values); err != nil {
+			return fmt.Errorf("parameter %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// collectQueryFields maps the JSON names of a struct's fields, embedded structs included
+func collectQueryFields(v reflect.Value, fields map[string]reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			collectQueryFields(v.Field(i), fields)
+			continue
+		}
+
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if !sf.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields[name] = v.Field(i)
+	}
+}
+
+func setQueryField(field reflect.Value, values []string) error {
+	if field.Kind() == reflect.Slice {
+		list := reflect.MakeSlice(field.Type(), 0, len(values))
+		for _, value := range values {
+			for _, item := range strings.Split(value, ",") {
+				elem := reflect.New(field.Type().Elem()).Elem()
+				if err := setQueryValue(elem, strings.TrimSpace(item)); err != nil {
+					return err
+				}
+				list = reflect.Append(list, elem)
+			}
+		}
+		field.Set(list)
+		return nil
+	}
+
+	if len(values) > 1 {
+		return fmt.Errorf("given more than once")
+	}
+	if field.Kind() == reflect.Pointer {
+		ptr := reflect.New(field.Type().Elem())
+		if err := setQueryValue(ptr.Elem(), values[0]); err != nil {
+			return err
+		}
+		field.Set(ptr)
+		return nil
+	}
+	return setQueryValue(field, values[0])
+}
+
+func setQueryValue(v reflect.Value, value string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", value)
+		}
+		v.SetInt(int64(n))
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		v.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", value)
+		}
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
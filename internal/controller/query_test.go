@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"flight-aggregator/internal/entity"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeQuery(t *testing.T) {
+	zero, one := 0, 1
+	returnDate := "2025-12-20"
+
+	tests := []struct {
+		name  string
+		query string
+		want  entity.SearchRequest
+	}{
+		{
+			name:  "plain fields",
+			query: "origin=CGK&departureDate=2025-12-15&passengers=2&cabinClass=economy&priceMax=1500000.50",
+			want:  entity.SearchRequest{Origin: "CGK", DepartureDate: "2025-12-15", Passanger: 2, CabinClass: "economy", PriceMax: 1500000.50},
+		},
+		{
+			name:  "repeated list",
+			query: "destinations=DPS&destinations=SUB",
+			want:  entity.SearchRequest{Destination: []string{"DPS", "SUB"}},
+		},
+		{
+			name:  "comma separated list",
+			query: "destinations=DPS,SUB",
+			want:  entity.SearchRequest{Destination: []string{"DPS", "SUB"}},
+		},
+		{
+			name:  "both list forms together, spaces trimmed",
+			query: "airlines=GA,%20JT&airlines=ID",
+			want:  entity.SearchRequest{Airlines: []string{"GA", "JT", "ID"}},
+		},
+		{
+			name:  "pointer set to zero is not left nil",
+			query: "maxStops=0",
+			want:  entity.SearchRequest{MaxStops: &zero},
+		},
+		{
+			name:  "pointer fields",
+			query: "maxStops=1&returnDate=2025-12-20",
+			want:  entity.SearchRequest{MaxStops: &one, ReturnDate: &returnDate},
+		},
+		{
+			name:  "pointer left nil when absent",
+			query: "origin=CGK",
+			want:  entity.SearchRequest{Origin: "CGK"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("parse %q: %v", tt.query, err)
+			}
+
+			var got entity.SearchRequest
+			if err := decodeQuery(query, &got); err != nil {
+				t.Fatalf("decodeQuery(%q): %v", tt.query, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeQuery(%q) = %+v, want %+v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeQueryErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{"int that is not a number", "passengers=two", `parameter "passengers": "two" is not a whole number`},
+		{"int with a fraction", "maxDuration=90.5", `parameter "maxDuration": "90.5" is not a whole number`},
+		{"pointer int", "maxStops=none", `parameter "maxStops": "none" is not a whole number`},
+		{"float", "priceMin=cheap", `parameter "priceMin": "cheap" is not a number`},
+		{"scalar given twice", "origin=CGK&origin=SUB", `parameter "origin": given more than once`},
+		{"unknown parameter", "destination=DPS", `unknown parameter "destination"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("parse %q: %v", tt.query, err)
+			}
+
+			var req entity.SearchRequest
+			err = decodeQuery(query, &req)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("decodeQuery(%q) err = %v, want %s", tt.query, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package entity
+
+// Airport is one entry of the airport dataset
+type Airport struct {
+	Code      string  `json:"code"`
+	Name      string  `json:"name"`
+	City      string  `json:"city"`
+	Country   string  `json:"country"`
+	Timezone  string  `json:"timezone"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// AirportLookup finds an airport by its IATA code
+type AirportLookup interface {
+	Get(code string) (Airport, bool)
+}
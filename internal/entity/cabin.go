@@ -0,0 +1,41 @@
+package entity
+
+import "strings"
+
+// Canonical cabins, every provider value is mapped to one of these
+const CABIN_ECONOMY = "economy"
+const CABIN_PREMIUM_ECONOMY = "premium_economy"
+const CABIN_BUSINESS = "business"
+const CABIN_FIRST = "first"
+
+// CABIN_UNKNOWN is set when a provider sends a value none of the tables know
+const CABIN_UNKNOWN = "unknown"
+
+// cabinNames accepts the canonical names and the usual ways of writing them
+var cabinNames = map[string]string{
+	"ECONOMY":         CABIN_ECONOMY,
+	"PREMIUM_ECONOMY": CABIN_PREMIUM_ECONOMY,
+	"PREMIUM ECONOMY": CABIN_PREMIUM_ECONOMY,
+	"PREMIUM-ECONOMY": CABIN_PREMIUM_ECONOMY,
+	"PREMIUM":         CABIN_PREMIUM_ECONOMY,
+	"BUSINESS":        CABIN_BUSINESS,
+	"FIRST":           CABIN_FIRST,
+}
+
+// NormalizeCabinClass maps a cabin name (e.g. "Economy", "premium economy") to the canonical cabin
+func NormalizeCabinClass(raw string) (string, bool) {
+	cabin, ok := cabinNames[strings.ToUpper(strings.TrimSpace(raw))]
+	return cabin, ok
+}
+
+// MapCabinClass looks raw up in a provider table (keys in upper case), then in the
+// canonical names. It returns CABIN_UNKNOWN when neither knows the value.
+func MapCabinClass(table map[string]string, raw string) string {
+	if cabin, ok := table[strings.ToUpper(strings.TrimSpace(raw))]; ok {
+		return cabin
+	}
+	if cabin, ok := NormalizeCabinClass(raw); ok {
+		return cabin
+	}
+	return CABIN_UNKNOWN
+}
@@ -1,7 +1,16 @@
 package entity
 
-type SearchCache struct {
-	Flights            []Flight `json:"flights"`
-	ProvidersSucceeded int      `json:"providers_succeeded"`
-	ProvidersFailed    int      `json:"providers_failed"`
+import "time"
+
+// Cache status of one provider in a search
+const (
+	CACHE_FRESH = "fresh"
+	CACHE_STALE = "stale"
+	CACHE_MISS  = "miss"
+)
+
+// CachedFlights is what is stored per airline, route and date
+type CachedFlights struct {
+	Flights  []Flight  `json:"flights"`
+	CachedAt time.Time `json:"cached_at"`
 }
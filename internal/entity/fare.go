@@ -0,0 +1,50 @@
+package entity
+
+// FARE BREAKDOWN SOURCES
+const (
+	FARE_ITEMIZED   = "itemized"
+	FARE_TOTAL_ONLY = "total_only"
+)
+
+// FareBreakdown splits the per-passenger amount into its components. Providers that
+// only quote a total are marked total_only and leave the components empty.
+type FareBreakdown struct {
+	Source     string  `json:"source"`
+	BaseFare   float64 `json:"base_fare,omitempty"`
+	Taxes      float64 `json:"taxes,omitempty"`
+	Surcharges float64 `json:"surcharges,omitempty"`
+}
+
+// ItemizedFare builds the breakdown of a provider that sends base fare and taxes.
+// Whatever the total holds on top of them is reported as surcharges. A breakdown
+// that does not add up to the total is not trusted and reported as total only.
+func ItemizedFare(base, taxes, total float64) *FareBreakdown {
+	if base <= 0 || taxes < 0 || base+taxes > total {
+		return TotalOnlyFare()
+	}
+	return &FareBreakdown{
+		Source:     FARE_ITEMIZED,
+		BaseFare:   base,
+		Taxes:      taxes,
+		Surcharges: total - base - taxes,
+	}
+}
+
+func TotalOnlyFare() *FareBreakdown {
+	return &FareBreakdown{Source: FARE_TOTAL_ONLY}
+}
+
+// SumFareBreakdowns adds up the breakdowns of several legs. The result is only
+// itemized when every leg is.
+func SumFareBreakdowns(breakdowns ...*FareBreakdown) *FareBreakdown {
+	sum := &FareBreakdown{Source: FARE_ITEMIZED}
+	for _, b := range breakdowns {
+		if b == nil || b.Source != FARE_ITEMIZED {
+			return TotalOnlyFare()
+		}
+		sum.BaseFare += b.BaseFare
+		sum.Taxes += b.Taxes
+		sum.Surcharges += b.Surcharges
+	}
+	return sum
+}
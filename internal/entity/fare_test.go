@@ -0,0 +1,49 @@
+package entity
+
+import "testing"
+
+func TestItemizedFare(t *testing.T) {
+	tests := []struct {
+		name               string
+		base, taxes, total float64
+		want               FareBreakdown
+	}{
+		{"base and taxes", 1000000, 150000, 1150000, FareBreakdown{Source: FARE_ITEMIZED, BaseFare: 1000000, Taxes: 150000}},
+		{"the rest is surcharges", 1000000, 150000, 1200000, FareBreakdown{Source: FARE_ITEMIZED, BaseFare: 1000000, Taxes: 150000, Surcharges: 50000}},
+		{"no taxes", 1000000, 0, 1000000, FareBreakdown{Source: FARE_ITEMIZED, BaseFare: 1000000}},
+		{"no base fare", 0, 150000, 1150000, FareBreakdown{Source: FARE_TOTAL_ONLY}},
+		{"negative taxes", 1000000, -1, 1000000, FareBreakdown{Source: FARE_TOTAL_ONLY}},
+		{"components over the total", 1000000, 150000, 1100000, FareBreakdown{Source: FARE_TOTAL_ONLY}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ItemizedFare(tt.base, tt.taxes, tt.total); *got != tt.want {
+				t.Fatalf("ItemizedFare = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSumFareBreakdowns(t *testing.T) {
+	batik := ItemizedFare(1000000, 150000, 1200000)
+	other := ItemizedFare(500000, 50000, 550000)
+
+	tests := []struct {
+		name string
+		legs []*FareBreakdown
+		want FareBreakdown
+	}{
+		{"every leg itemized", []*FareBreakdown{batik, other}, FareBreakdown{Source: FARE_ITEMIZED, BaseFare: 1500000, Taxes: 200000, Surcharges: 50000}},
+		{"one leg quotes a total", []*FareBreakdown{batik, TotalOnlyFare()}, FareBreakdown{Source: FARE_TOTAL_ONLY}},
+		{"one leg has none", []*FareBreakdown{batik, nil}, FareBreakdown{Source: FARE_TOTAL_ONLY}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SumFareBreakdowns(tt.legs...); *got != tt.want {
+				t.Fatalf("SumFareBreakdowns = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -1,25 +1,28 @@
 package entity
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 )
 
-const GARUDA = "Garuda"
-const LIONAIR = "LionAir"
-const BATIKAIR = "BatikAir"
-const AIRASIA = "AirAsia"
+// DateLayout is the format of every date field in a search request
+const DateLayout = "2006-01-02"
 
-const PROVIDER_LION_AIR = "Lion Air"
-const PROVIDER_GARUDA = "Garuda Indonesia"
-const PROVIDER_BATIK_AIR = "Batik Air"
-const PROVIDER_AIR_ASIA = "Air ASIA"
+// MaxSearchWaitMs is the longest deadline a request can ask for
+const MaxSearchWaitMs = 30000
 
 const AMENITIES_WIFI = "wifi"
 const AMENITIES_POWER_OUTLET = "power_outlet"
 const AMENITIES_MEAL = "meal"
 const AMENITIES_ENTERTAINMENT = "entertainment"
 
+// ErrInvalidRequest wraps every search validation error so callers can tell
+// a bad request apart from an internal failure
+var ErrInvalidRequest = errors.New("invalid search request")
+
 type Flight struct {
 	ID             string          `json:"id"`
 	Provider       string          `json:"provider"`
@@ -29,12 +32,26 @@ type Flight struct {
 	Arrival        LocationDetails `json:"arrival"`
 	Duration       DurationDetails `json:"duration"`
 	Stops          int             `json:"stops"`
+	Layovers       []Layover       `json:"layovers"`
 	Price          PriceDetails    `json:"price"`
 	AvailableSeats int             `json:"available_seats"`
-	CabinClass     string          `json:"cabin_class"`
-	Aircraft       *string         `json:"aircraft"`
-	Amenities      []string        `json:"amenities"`
-	Baggage        BaggageDetails  `json:"baggage"`
+	// CabinClass is one of the canonical CABIN_* values, FareClass is what the provider sent
+	CabinClass string         `json:"cabin_class"`
+	FareClass  string         `json:"fare_class"`
+	Aircraft   *string        `json:"aircraft"`
+	Amenities  []string       `json:"amenities"`
+	Baggage    BaggageDetails `json:"baggage"`
+}
+
+// Layover is a connection between two segments of a flight, in travel order.
+// ArrivalTime and DepartureTime are only set when the provider sends segment times.
+type Layover struct {
+	Airport         string     `json:"airport"`
+	AirportName     string     `json:"airport_name"`
+	City            string     `json:"city"`
+	DurationMinutes int        `json:"duration_minutes"`
+	ArrivalTime     *time.Time `json:"arrival_time,omitempty"`
+	DepartureTime   *time.Time `json:"departure_time,omitempty"`
 }
 
 type AirlineInfo struct {
@@ -43,54 +60,132 @@ type AirlineInfo struct {
 }
 
 type LocationDetails struct {
-	Airport   string    `json:"airport"`
-	City      string    `json:"city"`
+	Airport string `json:"airport"`
+	City    string `json:"city"`
+	// Datetime is in the airport's local time, Timezone is its IANA name
 	Datetime  time.Time `json:"datetime"`
 	Timestamp int64     `json:"timestamp"`
+	Timezone  string    `json:"timezone"`
 	Code      string
 }
 
+// LocalTime is Datetime in the airport's own zone, also for flights read back
+// from the cache where only the UTC offset survived
+func (l LocationDetails) LocalTime() time.Time {
+	if loc := LoadLocation(l.Timezone); loc != nil {
+		return l.Datetime.In(loc)
+	}
+	return l.Datetime
+}
+
 type DurationDetails struct {
 	TotalMinutes int    `json:"total_minutes"`
 	Formatted    string `json:"formatted"`
 }
 
-type PriceDetails struct {
+type OriginalPrice struct {
 	Amount    float64 `json:"amount"`
 	Currency  string  `json:"currency"`
 	Formatted string  `json:"formatted"`
 }
 
+// PriceDetails Amount is per passenger, TotalAmount is for the whole party
+type PriceDetails struct {
+	Amount         float64 `json:"amount"`
+	Currency       string  `json:"currency"`
+	Formatted      string  `json:"formatted"`
+	Passengers     int     `json:"passengers,omitempty"`
+	TotalAmount    float64 `json:"total_amount,omitempty"`
+	TotalFormatted string  `json:"total_formatted,omitempty"`
+	// Original is the price the provider quoted, set when it was converted to another currency
+	Original *OriginalPrice `json:"original,omitempty"`
+	// Breakdown splits Amount into base fare, taxes and surcharges
+	Breakdown *FareBreakdown `json:"breakdown,omitempty"`
+	// PassengerFares splits TotalAmount per passenger type
+	PassengerFares []PassengerFare `json:"passenger_fares,omitempty"`
+}
+
 type BaggageDetails struct {
 	CarryOn string `json:"carry_on"`
 	Checked string `json:"checked"`
 }
 
-type LocationRegistry struct{}
+// LocationRegistry is what the mappers use to fill airport names, cities and zones
+type LocationRegistry struct {
+	airports AirportLookup
+}
 
-var airportData = map[string]struct {
-	City    string
-	Airport string
-}{
-	"CGK": {City: "Jakarta", Airport: "Soekarno-Hatta International"},
-	"DPS": {City: "Denpasar", Airport: "Ngurah Rai International"},
-	"SUB": {City: "Surabaya", Airport: "Juanda International"},
-	"UPG": {City: "Makassar", Airport: "Sultan Hasanuddin International"},
-	"SOC": {City: "Solo", Airport: "Adi Soemarmo International"},
+func NewLocationRegistry(airports AirportLookup) LocationRegistry {
+	return LocationRegistry{airports: airports}
 }
 
-func (r *LocationRegistry) GetCity(code string) string {
-	if loc, ok := airportData[code]; ok {
-		return loc.City
+// lookup returns the zero Airport when the code is unknown
+func (r *LocationRegistry) lookup(code string) Airport {
+	if r.airports == nil {
+		return Airport{}
 	}
-	return ""
+	airport, _ := r.airports.Get(code)
+	return airport
+}
+
+func (r *LocationRegistry) GetCity(code string) string {
+	return r.lookup(code).City
 }
 
 func (r *LocationRegistry) GetAirport(code string) string {
-	if loc, ok := airportData[code]; ok {
-		return loc.Airport
+	return r.lookup(code).Name
+}
+
+// GetTimezone returns the IANA timezone of the airport (e.g. "Asia/Makassar" for WITA)
+func (r *LocationRegistry) GetTimezone(code string) string {
+	return r.lookup(code).Timezone
+}
+
+// Location returns the airport's time zone, nil when the airport or its zone is unknown
+func (r *LocationRegistry) Location(code string) *time.Location {
+	return LoadLocation(r.GetTimezone(code))
+}
+
+// InLocalTime expresses t in the airport's local time. t is returned as is when
+// the airport's zone is unknown.
+func (r *LocationRegistry) InLocalTime(code string, t time.Time) time.Time {
+	if loc := r.Location(code); loc != nil {
+		return t.In(loc)
+	}
+	return t
+}
+
+// ParseLocalTime reads a provider timestamp. One with an offset is taken as is,
+// one without is read as the airport's local time, so a provider may send either.
+func (r *LocationRegistry) ParseLocalTime(code, value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return r.InLocalTime(code, t), nil
+	}
+
+	loc := r.Location(code)
+	if loc == nil {
+		return time.Time{}, fmt.Errorf("time %q has no offset and airport %s has no known timezone", value, code)
 	}
-	return ""
+	return time.ParseInLocation("2006-01-02T15:04:05", value, loc)
+}
+
+var locationCache sync.Map
+
+// LoadLocation caches time.LoadLocation, which reads the zone database every call.
+// It returns nil for an empty or unknown name.
+func LoadLocation(name string) *time.Location {
+	if name == "" {
+		return nil
+	}
+	if loc, ok := locationCache.Load(name); ok {
+		return loc.(*time.Location)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil
+	}
+	locationCache.Store(name, loc)
+	return loc
 }
 
 type SearchRequest struct {
@@ -100,18 +195,40 @@ type SearchRequest struct {
 	ReturnDate    *string  `json:"returnDate"` // Pointer because it can be null
 	Passanger     int      `json:"passengers"`
 	CabinClass    string   `json:"cabinClass"`
+	// DisplayCurrency is the ISO code every price is converted to, priceMin and priceMax are in it too
+	DisplayCurrency string `json:"displayCurrency,omitempty"`
+
+	// Party per passenger type, passengers is the seated total when these are set
+	Adults   int `json:"adults,omitempty"`
+	Children int `json:"children,omitempty"`
+	Infants  int `json:"infants,omitempty"`
 
+	// Filters. Time windows (HH:MM) are in the airport's local time and wrap
+	// midnight when min is later than max, e.g. 22:00 to 02:00. PriceMin and
+	// PriceMax bound the total for the whole party.
 	PriceMin    float64  `json:"priceMin,omitempty"`
 	PriceMax    float64  `json:"priceMax,omitempty"`
 	MaxStops    *int     `json:"maxStops,omitempty"`
 	Airlines    []string `json:"airlines,omitempty"`
 	MinDepTime  string   `json:"minDepTime,omitempty"`
 	MaxDepTime  string   `json:"maxDepTime,omitempty"`
+	MinArrTime  string   `json:"minArrTime,omitempty"`
+	MaxArrTime  string   `json:"maxArrTime,omitempty"`
 	MaxDuration int      `json:"maxDuration,omitempty"`
 
+	// Layover filters, in minutes. A direct flight passes all of them.
+	MaxLayoverMinutes      int      `json:"maxLayoverMinutes,omitempty"`
+	MaxTotalLayoverMinutes int      `json:"maxTotalLayoverMinutes,omitempty"`
+	MinLayoverMinutes      int      `json:"minLayoverMinutes,omitempty"`
+	AvoidAirports          []string `json:"avoidAirports,omitempty"`
+
 	// Sorting
 	SortBy    string `json:"sortBy,omitempty"`
 	SortOrder string `json:"sortOrder,omitempty"`
+
+	// MaxWaitMs overrides the configured search deadline. Providers that have not
+	// answered by then are left out of the response and reported as timed out.
+	MaxWaitMs int `json:"maxWaitMs,omitempty"`
 }
 
 func (r *SearchRequest) Validate() error {
@@ -133,5 +250,181 @@ func (r *SearchRequest) Validate() error {
 		}
 	}
 
+	if err := r.validatePassengers(); err != nil {
+		return err
+	}
+
+	if r.CabinClass != "" {
+		if _, ok := NormalizeCabinClass(r.CabinClass); !ok {
+			return fmt.Errorf("cabinClass must be one of economy, premium_economy, business or first")
+		}
+	}
+
+	if r.DisplayCurrency != "" && len(r.DisplayCurrency) != 3 {
+		return fmt.Errorf("displayCurrency must be a 3-letter ISO currency code")
+	}
+
+	for name, value := range map[string]string{
+		"minDepTime": r.MinDepTime,
+		"maxDepTime": r.MaxDepTime,
+		"minArrTime": r.MinArrTime,
+		"maxArrTime": r.MaxArrTime,
+	} {
+		if _, err := ParseClock(value); value != "" && err != nil {
+			return fmt.Errorf("%s must be in HH:MM format", name)
+		}
+	}
+
+	if r.MaxWaitMs < 0 || r.MaxWaitMs > MaxSearchWaitMs {
+		return fmt.Errorf("maxWaitMs must be between 0 and %d", MaxSearchWaitMs)
+	}
+
+	if r.MaxLayoverMinutes < 0 || r.MaxTotalLayoverMinutes < 0 || r.MinLayoverMinutes < 0 {
+		return fmt.Errorf("layover limits cannot be negative")
+	}
+
+	if r.MaxLayoverMinutes > 0 && r.MinLayoverMinutes > r.MaxLayoverMinutes {
+		return fmt.Errorf("minLayoverMinutes cannot be greater than maxLayoverMinutes")
+	}
+
+	for _, airport := range r.AvoidAirports {
+		if len(strings.TrimSpace(airport)) != 3 {
+			return fmt.Errorf("avoid airport %s must be a 3-letter IATA code", airport)
+		}
+	}
+
+	departureDate, err := time.Parse(DateLayout, r.DepartureDate)
+	if err != nil {
+		return fmt.Errorf("departureDate must be in YYYY-MM-DD format")
+	}
+
+	if r.IsRoundTrip() {
+		returnDate, err := time.Parse(DateLayout, *r.ReturnDate)
+		if err != nil {
+			return fmt.Errorf("returnDate must be in YYYY-MM-DD format")
+		}
+		if returnDate.Before(departureDate) {
+			return fmt.Errorf("returnDate cannot be before departureDate")
+		}
+		if len(r.Destination) != 1 {
+			return fmt.Errorf("round trip search supports exactly one destination")
+		}
+	}
+
 	return nil
 }
+
+func (r *SearchRequest) IsRoundTrip() bool {
+	return r.ReturnDate != nil && *r.ReturnDate != ""
+}
+
+// ReturnLeg is the inbound half of a round trip with the same filters and sorting
+func (r SearchRequest) ReturnLeg() SearchRequest {
+	leg := r
+	leg.Origin = r.Destination[0]
+	leg.Destination = []string{r.Origin}
+	leg.DepartureDate = *r.ReturnDate
+	leg.ReturnDate = nil
+	return leg
+}
+
+// SearchLeg is one hop of a multi-city trip
+type SearchLeg struct {
+	Origin        string `json:"origin"`
+	Destination   string `json:"destination"`
+	DepartureDate string `json:"departureDate"`
+}
+
+// MultiCitySearchRequest shares passengers, cabin, filters and sorting of SearchRequest
+// across every leg. Origin, destinations and dates come from Legs instead.
+type MultiCitySearchRequest struct {
+	SearchRequest
+	Legs []SearchLeg `json:"legs"`
+	// MinConnectionMinutes is the minimum time between landing and the next leg's departure
+	MinConnectionMinutes int `json:"minConnectionMinutes,omitempty"`
+}
+
+const MinMultiCityLegs = 2
+const MaxMultiCityLegs = 6
+
+func (r *MultiCitySearchRequest) Validate() error {
+	if len(r.Legs) < MinMultiCityLegs || len(r.Legs) > MaxMultiCityLegs {
+		return fmt.Errorf("multi-city search needs between %d and %d legs", MinMultiCityLegs, MaxMultiCityLegs)
+	}
+
+	if r.MinConnectionMinutes < 0 {
+		return fmt.Errorf("minConnectionMinutes cannot be negative")
+	}
+
+	var previous time.Time
+	for i, leg := range r.Legs {
+		legReq := r.LegRequest(i)
+		if err := legReq.Validate(); err != nil {
+			return fmt.Errorf("leg %d: %w", i+1, err)
+		}
+
+		date, _ := time.Parse(DateLayout, leg.DepartureDate)
+		if date.Before(previous) {
+			return fmt.Errorf("leg %d departs before leg %d", i+1, i)
+		}
+		previous = date
+	}
+
+	return nil
+}
+
+// LegRequest is the one-way search of leg i with the shared filters and sorting
+func (r *MultiCitySearchRequest) LegRequest(i int) SearchRequest {
+	leg := r.SearchRequest
+	leg.Origin = r.Legs[i].Origin
+	leg.Destination = []string{r.Legs[i].Destination}
+	leg.DepartureDate = r.Legs[i].DepartureDate
+	leg.ReturnDate = nil
+	return leg
+}
+
+// FareCalendarRequest searches every day from DepartureDate-WindowDays to DepartureDate+WindowDays
+type FareCalendarRequest struct {
+	SearchRequest
+	// WindowDays is a pointer so 0, the departure date alone, is told apart from not given
+	WindowDays *int `json:"windowDays,omitempty"`
+}
+
+const DefaultFareCalendarWindowDays = 3
+const MaxFareCalendarWindowDays = 7
+
+func (r *FareCalendarRequest) Validate() error {
+	if err := r.SearchRequest.Validate(); err != nil {
+		return err
+	}
+
+	if r.IsRoundTrip() {
+		return fmt.Errorf("fare calendar does not support returnDate")
+	}
+
+	if window := r.Window(); window < 0 || window > MaxFareCalendarWindowDays {
+		return fmt.Errorf("windowDays must be between 0 and %d", MaxFareCalendarWindowDays)
+	}
+
+	return nil
+}
+
+// Window is the number of days searched on each side of DepartureDate,
+// DefaultFareCalendarWindowDays when windowDays was not given
+func (r *FareCalendarRequest) Window() int {
+	if r.WindowDays == nil {
+		return DefaultFareCalendarWindowDays
+	}
+	return *r.WindowDays
+}
+
+// Dates lists every day of the window in order
+func (r *FareCalendarRequest) Dates() []string {
+	window := r.Window()
+	center, _ := time.Parse(DateLayout, r.DepartureDate)
+	dates := make([]string, 0, window*2+1)
+	for offset := -window; offset <= window; offset++ {
+		dates = append(dates, center.AddDate(0, 0, offset).Format(DateLayout))
+	}
+	return dates
+}
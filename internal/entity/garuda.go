@@ -30,19 +30,33 @@ type Baggage struct {
 }
 
 type GarudaFlight struct {
-	FlightID        string    `json:"flight_id"`
-	Airline         string    `json:"airline"`
-	AirlineCode     string    `json:"airline_code"`
-	Departure       Departure `json:"departure"`
-	Arrival         Arrival   `json:"arrival"`
-	DurationMinutes int       `json:"duration_minutes"`
-	Stops           int       `json:"stops"`
-	Aircraft        string    `json:"aircraft"`
-	Price           Price     `json:"price"`
-	AvailableSeats  int       `json:"available_seats"`
-	FareClass       string    `json:"fare_class"`
-	Baggage         Baggage   `json:"baggage"`
-	Amenities       []string  `json:"amenities"`
+	FlightID        string          `json:"flight_id"`
+	Airline         string          `json:"airline"`
+	AirlineCode     string          `json:"airline_code"`
+	Departure       Departure       `json:"departure"`
+	Arrival         Arrival         `json:"arrival"`
+	DurationMinutes int             `json:"duration_minutes"`
+	Stops           int             `json:"stops"`
+	Aircraft        string          `json:"aircraft"`
+	Price           Price           `json:"price"`
+	Segments        []GarudaSegment `json:"segments,omitempty"`
+	AvailableSeats  int             `json:"available_seats"`
+	FareClass       string          `json:"fare_class"`
+	Baggage         Baggage         `json:"baggage"`
+	Amenities       []string        `json:"amenities"`
+}
+
+type GarudaSegment struct {
+	FlightNumber    string             `json:"flight_number"`
+	Departure       GarudaSegmentPoint `json:"departure"`
+	Arrival         GarudaSegmentPoint `json:"arrival"`
+	DurationMinutes int                `json:"duration_minutes"`
+	LayoverMinutes  int                `json:"layover_minutes,omitempty"`
+}
+
+type GarudaSegmentPoint struct {
+	Airport string `json:"airport"`
+	Time    string `json:"time"`
 }
 
 func (f *GarudaFlight) Validate() error {
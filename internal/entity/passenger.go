@@ -0,0 +1,67 @@
+package entity
+
+import "fmt"
+
+// PASSENGER TYPES
+const (
+	PAX_ADULT  = "ADT"
+	PAX_CHILD  = "CHD"
+	PAX_INFANT = "INF"
+)
+
+// MaxPassengers is the most seats one search can book. Infants sit on an adult's
+// lap so they do not count against it.
+const MaxPassengers = 9
+
+// PassengerFare is the price of every passenger of one type in the party
+type PassengerFare struct {
+	Type           string  `json:"type"`
+	Count          int     `json:"count"`
+	Amount         float64 `json:"amount"`
+	Formatted      string  `json:"formatted"`
+	TotalAmount    float64 `json:"total_amount"`
+	TotalFormatted string  `json:"total_formatted"`
+}
+
+// HasPassengerMix tells whether the party was given per passenger type instead of
+// a plain passengers count
+func (r *SearchRequest) HasPassengerMix() bool {
+	return r.Adults != 0 || r.Children != 0 || r.Infants != 0
+}
+
+// SeatedPassengers is the number of seats the party needs
+func (r *SearchRequest) SeatedPassengers() int {
+	return r.Adults + r.Children
+}
+
+func (r *SearchRequest) validatePassengers() error {
+	if r.Passanger < 0 || r.Adults < 0 || r.Children < 0 || r.Infants < 0 {
+		return fmt.Errorf("passenger counts cannot be negative")
+	}
+
+	// no passenger field at all is one adult
+	if !r.HasPassengerMix() {
+		if r.Passanger > MaxPassengers {
+			return fmt.Errorf("passengers must be between 1 and %d", MaxPassengers)
+		}
+		return nil
+	}
+
+	if r.Adults == 0 {
+		return fmt.Errorf("at least one adult is required")
+	}
+
+	if r.Infants > r.Adults {
+		return fmt.Errorf("infants cannot outnumber adults")
+	}
+
+	if r.SeatedPassengers() > MaxPassengers {
+		return fmt.Errorf("adults and children together cannot be more than %d", MaxPassengers)
+	}
+
+	if r.Passanger != 0 && r.Passanger != r.SeatedPassengers() {
+		return fmt.Errorf("passengers must equal adults + children when both are given")
+	}
+
+	return nil
+}
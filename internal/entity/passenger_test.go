@@ -0,0 +1,37 @@
+package entity
+
+import "testing"
+
+func TestValidatePassengers(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     SearchRequest
+		wantErr string
+	}{
+		{"no passenger field is one adult", SearchRequest{}, ""},
+		{"plain count", SearchRequest{Passanger: 4}, ""},
+		{"plain count at the limit", SearchRequest{Passanger: MaxPassengers}, ""},
+		{"plain count over the limit", SearchRequest{Passanger: MaxPassengers + 1}, "passengers must be between 1 and 9"},
+		{"negative count", SearchRequest{Passanger: -1}, "passenger counts cannot be negative"},
+
+		{"family", SearchRequest{Adults: 2, Children: 1, Infants: 1}, ""},
+		{"family with a matching total", SearchRequest{Passanger: 3, Adults: 2, Children: 1}, ""},
+		{"family with another total", SearchRequest{Passanger: 4, Adults: 2, Children: 1}, "passengers must equal adults + children when both are given"},
+		{"children alone", SearchRequest{Children: 2}, "at least one adult is required"},
+		{"more infants than adults", SearchRequest{Adults: 1, Infants: 2}, "infants cannot outnumber adults"},
+		{"infants do not take a seat", SearchRequest{Adults: 5, Children: 4, Infants: 5}, ""},
+		{"too many seats", SearchRequest{Adults: 5, Children: 5}, "adults and children together cannot be more than 9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.validatePassengers()
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("validatePassengers() = %v, want nil", err)
+			case tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr):
+				t.Errorf("validatePassengers() = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -5,14 +5,31 @@ type SearchResponse struct {
 	Metadata       Metadata       `json:"metadata"`
 	BestValue      *Flight        `json:"best_value_deal"`
 	Flights        []Flight       `json:"flights"`
+
+	// Round trip only
+	ReturnFlights      []Flight   `json:"return_flights,omitempty"`
+	CheapestRoundTrip  *Itinerary `json:"cheapest_round_trip,omitempty"`
+	BestValueRoundTrip *Itinerary `json:"best_value_round_trip,omitempty"`
+}
+
+// Itinerary is a set of flights booked together, in travel order
+type Itinerary struct {
+	Legs          []Flight        `json:"legs"`
+	TotalPrice    PriceDetails    `json:"total_price"`
+	TotalDuration DurationDetails `json:"total_duration"`
 }
 
 type SearchCriteria struct {
 	Origin        string   `json:"origin"`
 	Destination   []string `json:"destination"`
 	DepartureDate string   `json:"departure_date"`
+	ReturnDate    *string  `json:"return_date,omitempty"`
 	Passengers    int      `json:"passengers"`
+	Adults        int      `json:"adults"`
+	Children      int      `json:"children"`
+	Infants       int      `json:"infants"`
 	CabinClass    string   `json:"cabin_class"`
+	Currency      string   `json:"currency"`
 }
 
 type Metadata struct {
@@ -21,5 +38,49 @@ type Metadata struct {
 	ProvidersSucceeded int   `json:"providers_succeeded"`
 	ProvidersFailed    int   `json:"providers_failed"`
 	SearchTimeMs       int64 `json:"search_time_ms"`
-	CacheHit           bool  `json:"cache_hit"`
+	// CacheStatus is fresh, stale or miss per provider
+	CacheStatus map[string]string `json:"cache_status"`
+	// CacheHealthy is false when redis is unreachable and the search ran on the in-memory cache
+	CacheHealthy bool `json:"cache_healthy"`
+	// ProvidersSkipped were not called because their circuit breaker is open
+	ProvidersSkipped []string `json:"providers_skipped,omitempty"`
+	// ProvidersTimedOut had not answered by the search deadline, their flights are
+	// missing from this response but cached for the next search
+	ProvidersTimedOut []string `json:"providers_timed_out,omitempty"`
+	// ProviderRetries counts the retries made per provider, absent when none were needed
+	ProviderRetries map[string]int `json:"provider_retries,omitempty"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+type MultiCitySearchResponse struct {
+	Metadata           Metadata    `json:"metadata"`
+	Legs               []LegResult `json:"legs"`
+	BestValueItinerary *Itinerary  `json:"best_value_itinerary"`
+	// Itineraries are sorted by total price, cheapest first
+	Itineraries []Itinerary `json:"itineraries"`
+}
+
+type LegResult struct {
+	SearchCriteria SearchCriteria `json:"search_criteria"`
+	BestValue      *Flight        `json:"best_value_deal"`
+	Flights        []Flight       `json:"flights"`
+}
+
+type FareCalendarResponse struct {
+	SearchCriteria SearchCriteria    `json:"search_criteria"`
+	Metadata       Metadata          `json:"metadata"`
+	Days           []FareCalendarDay `json:"days"`
+}
+
+// FareCalendarDay is nil on Cheapest and BestValue when nothing flies that day
+type FareCalendarDay struct {
+	Date         string  `json:"date"`
+	Cheapest     *Flight `json:"cheapest"`
+	BestValue    *Flight `json:"best_value_deal"`
+	TotalResults int     `json:"total_results"`
+	// CacheStatus is fresh, stale or miss per provider for this day
+	CacheStatus map[string]string `json:"cache_status"`
 }
@@ -0,0 +1,52 @@
+package entity
+
+// Outcome of one provider in a search
+const (
+	PROVIDER_OK        = "ok"
+	PROVIDER_FAILED    = "failed"
+	PROVIDER_SKIPPED   = "skipped"
+	PROVIDER_TIMED_OUT = "timed_out"
+)
+
+// Event types of a streaming search
+const (
+	EVENT_PROVIDER   = "provider"
+	EVENT_BEST_VALUE = "best_value"
+	EVENT_SUMMARY    = "summary"
+)
+
+// SearchEvent is one step of a streaming search, only the field of its type is set
+type SearchEvent struct {
+	Type      string
+	Provider  *ProviderResult
+	BestValue *Flight
+	Summary   *SearchSummary
+}
+
+// Data is the payload sent for the event
+func (e SearchEvent) Data() interface{} {
+	switch e.Type {
+	case EVENT_PROVIDER:
+		return e.Provider
+	case EVENT_BEST_VALUE:
+		return e.BestValue
+	default:
+		return e.Summary
+	}
+}
+
+// ProviderResult is what one provider answered, its flights already filtered and sorted
+type ProviderResult struct {
+	Provider    string   `json:"provider"`
+	Status      string   `json:"status"`
+	CacheStatus string   `json:"cache_status"`
+	Retries     int      `json:"retries,omitempty"`
+	Flights     []Flight `json:"flights"`
+}
+
+// SearchSummary closes a streaming search once every provider has answered or timed out
+type SearchSummary struct {
+	SearchCriteria SearchCriteria `json:"search_criteria"`
+	Metadata       Metadata       `json:"metadata"`
+	BestValue      *Flight        `json:"best_value_deal"`
+}
@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// ParseClock reads an "HH:MM" time of day as minutes after midnight
+func ParseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// InTimeWindow tells whether the clock time of t falls within min and max, both
+// "HH:MM" and inclusive. An empty bound is open. When min is later than max the
+// window wraps midnight, so 22:00 to 02:00 takes 23:30 and 01:15 but not 12:00.
+func InTimeWindow(t time.Time, min, max string) bool {
+	clock := t.Hour()*60 + t.Minute()
+
+	from, fromErr := ParseClock(min)
+	to, toErr := ParseClock(max)
+	hasFrom := min != "" && fromErr == nil
+	hasTo := max != "" && toErr == nil
+
+	switch {
+	case hasFrom && hasTo && from > to:
+		return clock >= from || clock <= to
+	case hasFrom && hasTo:
+		return clock >= from && clock <= to
+	case hasFrom:
+		return clock >= from
+	case hasTo:
+		return clock <= to
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInTimeWindow(t *testing.T) {
+	wib := time.FixedZone("WIB", 7*3600)
+	wita := time.FixedZone("WITA", 8*3600)
+	at := func(hour, minute int, loc *time.Location) time.Time {
+		return time.Date(2025, 12, 15, hour, minute, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name     string
+		t        time.Time
+		min, max string
+		want     bool
+	}{
+		{"inside", at(9, 30, wib), "06:00", "12:00", true},
+		{"on the lower bound", at(6, 0, wib), "06:00", "12:00", true},
+		{"on the upper bound", at(12, 0, wib), "06:00", "12:00", true},
+		{"a minute past the upper bound", at(12, 1, wib), "06:00", "12:00", false},
+		{"before the lower bound", at(5, 59, wib), "06:00", "12:00", false},
+
+		{"wraps midnight, late evening", at(23, 30, wib), "22:00", "02:00", true},
+		{"wraps midnight, early morning", at(1, 15, wib), "22:00", "02:00", true},
+		{"wraps midnight, on midnight", at(0, 0, wib), "22:00", "02:00", true},
+		{"wraps midnight, midday", at(12, 0, wib), "22:00", "02:00", false},
+		{"wraps midnight, just after max", at(2, 1, wib), "22:00", "02:00", false},
+		{"wraps midnight, just before min", at(21, 59, wib), "22:00", "02:00", false},
+
+		{"min equals max, on it", at(8, 0, wib), "08:00", "08:00", true},
+		{"min equals max, a minute off", at(8, 1, wib), "08:00", "08:00", false},
+
+		{"only min", at(20, 0, wib), "18:00", "", true},
+		{"only min, before it", at(17, 0, wib), "18:00", "", false},
+		{"only max", at(7, 0, wib), "", "09:00", true},
+		{"only max, after it", at(10, 0, wib), "", "09:00", false},
+		{"no bounds", at(3, 0, wib), "", "", true},
+		{"an unreadable bound is open", at(3, 0, wib), "late", "09:00", true},
+
+		// arrival times are compared in the arrival airport's own zone: 01:30 in Bali
+		// is 00:30 in Jakarta and 17:30 UTC the day before
+		{"local arrival inside", at(1, 30, wita), "01:00", "02:00", true},
+		{"same instant in Jakarta", at(1, 30, wita).In(wib), "01:00", "02:00", false},
+		{"same instant in UTC", at(1, 30, wita).UTC(), "17:00", "18:00", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InTimeWindow(tt.t, tt.min, tt.max); got != tt.want {
+				t.Errorf("InTimeWindow(%s, %q, %q) = %v, want %v", tt.t.Format("15:04 MST"), tt.min, tt.max, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInTimeWindowArrivalLocalTime(t *testing.T) {
+	// lands 01:30 in Bali, stored in UTC as the providers' timestamps are
+	arrival := LocationDetails{
+		Airport:  "DPS",
+		Datetime: time.Date(2025, 12, 14, 17, 30, 0, 0, time.UTC),
+		Timezone: "Asia/Makassar",
+	}
+
+	if !InTimeWindow(arrival.LocalTime(), "01:00", "02:00") {
+		t.Errorf("arrival at %s not in 01:00-02:00 local", arrival.LocalTime().Format(time.RFC3339))
+	}
+	if InTimeWindow(arrival.LocalTime(), "17:00", "18:00") {
+		t.Error("arrival matched its UTC clock time instead of the local one")
+	}
+}
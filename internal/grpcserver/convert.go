@@ -0,0 +1,245 @@
+package grpcserver
+
+import (
+	flightv1 "flight-aggregator/api/flight/v1"
+	"flight-aggregator/internal/entity"
+	"time"
+)
+
+// toSearchRequest maps the protobuf request onto the one the JSON API decodes, validation is left to the service
+func toSearchRequest(req *flightv1.SearchRequest) entity.SearchRequest {
+	search := entity.SearchRequest{
+		Origin:                 req.GetOrigin(),
+		Destination:            req.GetDestinations(),
+		DepartureDate:          req.GetDepartureDate(),
+		ReturnDate:             req.ReturnDate,
+		Passanger:              int(req.GetPassengers()),
+		CabinClass:             req.GetCabinClass(),
+		DisplayCurrency:        req.GetDisplayCurrency(),
+		Adults:                 int(req.GetAdults()),
+		Children:               int(req.GetChildren()),
+		Infants:                int(req.GetInfants()),
+		PriceMin:               req.GetPriceMin(),
+		PriceMax:               req.GetPriceMax(),
+		Airlines:               req.GetAirlines(),
+		MinDepTime:             req.GetMinDepTime(),
+		MaxDepTime:             req.GetMaxDepTime(),
+		MinArrTime:             req.GetMinArrTime(),
+		MaxArrTime:             req.GetMaxArrTime(),
+		MaxDuration:            int(req.GetMaxDuration()),
+		MaxLayoverMinutes:      int(req.GetMaxLayoverMinutes()),
+		MaxTotalLayoverMinutes: int(req.GetMaxTotalLayoverMinutes()),
+		MinLayoverMinutes:      int(req.GetMinLayoverMinutes()),
+		AvoidAirports:          req.GetAvoidAirports(),
+		SortBy:                 req.GetSortBy(),
+		SortOrder:              req.GetSortOrder(),
+		MaxWaitMs:              int(req.GetMaxWaitMs()),
+	}
+	if req.MaxStops != nil {
+		maxStops := int(req.GetMaxStops())
+		search.MaxStops = &maxStops
+	}
+	return search
+}
+
+func fromSearchResponse(res entity.SearchResponse) *flightv1.SearchResponse {
+	return &flightv1.SearchResponse{
+		SearchCriteria:     fromSearchCriteria(res.SearchCriteria),
+		Metadata:           fromMetadata(res.Metadata),
+		BestValueDeal:      fromFlightPtr(res.BestValue),
+		Flights:            fromFlights(res.Flights),
+		ReturnFlights:      fromFlights(res.ReturnFlights),
+		CheapestRoundTrip:  fromItinerary(res.CheapestRoundTrip),
+		BestValueRoundTrip: fromItinerary(res.BestValueRoundTrip),
+	}
+}
+
+func fromSearchEvent(event entity.SearchEvent) *flightv1.SearchEvent {
+	switch event.Type {
+	case entity.EVENT_PROVIDER:
+		return &flightv1.SearchEvent{Event: &flightv1.SearchEvent_Provider{Provider: &flightv1.ProviderResult{
+			Provider:    event.Provider.Provider,
+			Status:      event.Provider.Status,
+			CacheStatus: event.Provider.CacheStatus,
+			Retries:     int32(event.Provider.Retries),
+			Flights:     fromFlights(event.Provider.Flights),
+		}}}
+	case entity.EVENT_BEST_VALUE:
+		return &flightv1.SearchEvent{Event: &flightv1.SearchEvent_BestValue{BestValue: fromFlightPtr(event.BestValue)}}
+	default:
+		return &flightv1.SearchEvent{Event: &flightv1.SearchEvent_Summary{Summary: &flightv1.SearchSummary{
+			SearchCriteria: fromSearchCriteria(event.Summary.SearchCriteria),
+			Metadata:       fromMetadata(event.Summary.Metadata),
+			BestValueDeal:  fromFlightPtr(event.Summary.BestValue),
+		}}}
+	}
+}
+
+func fromSearchCriteria(criteria entity.SearchCriteria) *flightv1.SearchCriteria {
+	return &flightv1.SearchCriteria{
+		Origin:        criteria.Origin,
+		Destination:   criteria.Destination,
+		DepartureDate: criteria.DepartureDate,
+		ReturnDate:    criteria.ReturnDate,
+		Passengers:    int32(criteria.Passengers),
+		Adults:        int32(criteria.Adults),
+		Children:      int32(criteria.Children),
+		Infants:       int32(criteria.Infants),
+		CabinClass:    criteria.CabinClass,
+		Currency:      criteria.Currency,
+	}
+}
+
+func fromMetadata(meta entity.Metadata) *flightv1.Metadata {
+	var retries map[string]int32
+	if len(meta.ProviderRetries) > 0 {
+		retries = make(map[string]int32, len(meta.ProviderRetries))
+		for code, count := range meta.ProviderRetries {
+			retries[code] = int32(count)
+		}
+	}
+
+	return &flightv1.Metadata{
+		TotalResults:       int32(meta.TotalResults),
+		ProvidersQueried:   int32(meta.ProvidersQueried),
+		ProvidersSucceeded: int32(meta.ProvidersSucceeded),
+		ProvidersFailed:    int32(meta.ProvidersFailed),
+		SearchTimeMs:       meta.SearchTimeMs,
+		CacheStatus:        meta.CacheStatus,
+		CacheHealthy:       meta.CacheHealthy,
+		ProvidersSkipped:   meta.ProvidersSkipped,
+		ProvidersTimedOut:  meta.ProvidersTimedOut,
+		ProviderRetries:    retries,
+	}
+}
+
+func fromItinerary(itinerary *entity.Itinerary) *flightv1.Itinerary {
+	if itinerary == nil {
+		return nil
+	}
+	return &flightv1.Itinerary{
+		Legs:          fromFlights(itinerary.Legs),
+		TotalPrice:    fromPrice(itinerary.TotalPrice),
+		TotalDuration: fromDuration(itinerary.TotalDuration),
+	}
+}
+
+func fromFlights(flights []entity.Flight) []*flightv1.Flight {
+	if flights == nil {
+		return nil
+	}
+	out := make([]*flightv1.Flight, len(flights))
+	for i := range flights {
+		out[i] = fromFlight(flights[i])
+	}
+	return out
+}
+
+func fromFlightPtr(fl *entity.Flight) *flightv1.Flight {
+	if fl == nil {
+		return nil
+	}
+	return fromFlight(*fl)
+}
+
+func fromFlight(fl entity.Flight) *flightv1.Flight {
+	layovers := make([]*flightv1.Layover, len(fl.Layovers))
+	for i, stop := range fl.Layovers {
+		layovers[i] = &flightv1.Layover{
+			Airport:         stop.Airport,
+			AirportName:     stop.AirportName,
+			City:            stop.City,
+			DurationMinutes: int32(stop.DurationMinutes),
+			ArrivalTime:     formatTimePtr(stop.ArrivalTime),
+			DepartureTime:   formatTimePtr(stop.DepartureTime),
+		}
+	}
+
+	return &flightv1.Flight{
+		Id:       fl.ID,
+		Provider: fl.Provider,
+		Airline: &flightv1.AirlineInfo{
+			Name: fl.Airline.Name,
+			Code: fl.Airline.Code,
+		},
+		FlightNumber:   fl.FlightNumber,
+		Departure:      fromLocation(fl.Departure),
+		Arrival:        fromLocation(fl.Arrival),
+		Duration:       fromDuration(fl.Duration),
+		Stops:          int32(fl.Stops),
+		Layovers:       layovers,
+		Price:          fromPrice(fl.Price),
+		AvailableSeats: int32(fl.AvailableSeats),
+		CabinClass:     fl.CabinClass,
+		FareClass:      fl.FareClass,
+		Aircraft:       fl.Aircraft,
+		Amenities:      fl.Amenities,
+		Baggage: &flightv1.BaggageDetails{
+			CarryOn: fl.Baggage.CarryOn,
+			Checked: fl.Baggage.Checked,
+		},
+	}
+}
+
+func fromLocation(loc entity.LocationDetails) *flightv1.LocationDetails {
+	return &flightv1.LocationDetails{
+		Airport:   loc.Airport,
+		City:      loc.City,
+		Datetime:  loc.LocalTime().Format(time.RFC3339),
+		Timestamp: loc.Timestamp,
+		Timezone:  loc.Timezone,
+		Code:      loc.Code,
+	}
+}
+
+func fromDuration(duration entity.DurationDetails) *flightv1.DurationDetails {
+	return &flightv1.DurationDetails{
+		TotalMinutes: int32(duration.TotalMinutes),
+		Formatted:    duration.Formatted,
+	}
+}
+
+func fromPrice(price entity.PriceDetails) *flightv1.PriceDetails {
+	out := &flightv1.PriceDetails{
+		Amount:         price.Amount,
+		Currency:       price.Currency,
+		Formatted:      price.Formatted,
+		Passengers:     int32(price.Passengers),
+		TotalAmount:    price.TotalAmount,
+		TotalFormatted: price.TotalFormatted,
+	}
+	if price.Original != nil {
+		out.Original = &flightv1.OriginalPrice{
+			Amount:    price.Original.Amount,
+			Currency:  price.Original.Currency,
+			Formatted: price.Original.Formatted,
+		}
+	}
+	if price.Breakdown != nil {
+		out.Breakdown = &flightv1.FareBreakdown{
+			Source:     price.Breakdown.Source,
+			BaseFare:   price.Breakdown.BaseFare,
+			Taxes:      price.Breakdown.Taxes,
+			Surcharges: price.Breakdown.Surcharges,
+		}
+	}
+	for _, fare := range price.PassengerFares {
+		out.PassengerFares = append(out.PassengerFares, &flightv1.PassengerFare{
+			Type:           fare.Type,
+			Count:          int32(fare.Count),
+			Amount:         fare.Amount,
+			Formatted:      fare.Formatted,
+			TotalAmount:    fare.TotalAmount,
+			TotalFormatted: fare.TotalFormatted,
+		})
+	}
+	return out
+}
+
+func formatTimePtr(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.Format(time.RFC3339)
+	return &formatted
+}
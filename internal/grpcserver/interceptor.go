@@ -0,0 +1,67 @@
+package grpcserver
+
+import (
+	"context"
+	logger "flight-aggregator/internal/common"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDKey is the metadata key of the request ID, the gRPC form of X-Request-ID
+const requestIDKey = "x-request-id"
+
+// withRequestID puts the caller's x-request-id, or a new one, in the call context and
+// sends it back in the response headers
+func withRequestID(ctx context.Context) context.Context {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDKey); len(values) > 0 {
+			id = values[0]
+		}
+	}
+	id = logger.KeepOrNewRequestID(id)
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, id))
+	return logger.WithRequestID(ctx, id)
+}
+
+func unaryRequestID(log *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = withRequestID(ctx)
+		startTime := time.Now()
+		res, err := handler(ctx, req)
+		logCall(ctx, log, info.FullMethod, startTime, err)
+		return res, err
+	}
+}
+
+func streamRequestID(log *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := withRequestID(stream.Context())
+		startTime := time.Now()
+		err := handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+		logCall(ctx, log, info.FullMethod, startTime, err)
+		return err
+	}
+}
+
+func logCall(ctx context.Context, log *slog.Logger, method string, startTime time.Time, err error) {
+	log.InfoContext(ctx, "call served",
+		"method", method,
+		"code", status.Code(err).String(),
+		"duration_ms", time.Since(startTime).Milliseconds(),
+	)
+}
+
+// contextStream hands the handler a context that carries the request ID
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,89 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	flightv1 "flight-aggregator/api/flight/v1"
+	"flight-aggregator/internal/entity"
+	"flight-aggregator/internal/service"
+	"log/slog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FlightServer serves the flight search over gRPC, next to the HTTP controllers.
+// The call context, deadline included, is handed to FlightService as is, which
+// passes the deadline on to the provider calls.
+type FlightServer struct {
+	flightv1.UnimplementedFlightServiceServer
+	flightService service.FlightService
+	logger        *slog.Logger
+}
+
+func NewFlightServer(flightService service.FlightService, logger *slog.Logger) *FlightServer {
+	return &FlightServer{
+		flightService: flightService,
+		logger:        logger,
+	}
+}
+
+// New returns a gRPC server with the flight API registered. It serves on any
+// net.Listener, a bufconn listener for in-process tests included. Every call gets
+// a request ID, from the x-request-id metadata or a new one.
+func New(flightService service.FlightService, logger *slog.Logger, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(unaryRequestID(logger)),
+		grpc.ChainStreamInterceptor(streamRequestID(logger)),
+	)
+	server := grpc.NewServer(opts...)
+	flightv1.RegisterFlightServiceServer(server, NewFlightServer(flightService, logger))
+	return server
+}
+
+// Search handles FlightService/Search
+func (s *FlightServer) Search(ctx context.Context, req *flightv1.SearchRequest) (*flightv1.SearchResponse, error) {
+	s.logger.DebugContext(ctx, "search flights")
+
+	result, err := s.flightService.SearchFlight(ctx, toSearchRequest(req))
+	if err != nil {
+		return nil, s.searchError(ctx, err)
+	}
+	return fromSearchResponse(result), nil
+}
+
+// SearchStream handles FlightService/SearchStream
+func (s *FlightServer) SearchStream(req *flightv1.SearchRequest, stream flightv1.FlightService_SearchStreamServer) error {
+	s.logger.DebugContext(stream.Context(), "stream flight search")
+
+	events, err := s.flightService.SearchFlightStream(stream.Context(), toSearchRequest(req))
+	if err != nil {
+		return s.searchError(stream.Context(), err)
+	}
+
+	for event := range events {
+		if err := stream.Send(fromSearchEvent(event)); err != nil {
+			// the client is gone, its context cancels the search
+			return err
+		}
+	}
+
+	// the stream also ends early, without a summary, when the call is cancelled or runs out of time
+	if err := stream.Context().Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return nil
+}
+
+// searchError maps validation errors to InvalidArgument and everything else to Internal
+func (s *FlightServer) searchError(ctx context.Context, err error) error {
+	if errors.Is(err, entity.ErrInvalidRequest) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	s.logger.ErrorContext(ctx, "search failed", "error", err)
+	return status.Error(codes.Internal, "failed to search flights")
+}
@@ -0,0 +1,296 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	flightv1 "flight-aggregator/api/flight/v1"
+	"flight-aggregator/internal/entity"
+	"flight-aggregator/internal/redis"
+	"flight-aggregator/internal/service"
+	"flight-aggregator/internal/service/provider"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// stubFlightService answers every search with one flight and rejects a request without an origin
+type stubFlightService struct {
+	// hadDeadline records whether the last search context carried the call deadline
+	hadDeadline atomic.Bool
+}
+
+func (s *stubFlightService) validate(ctx context.Context, req entity.SearchRequest) error {
+	_, ok := ctx.Deadline()
+	s.hadDeadline.Store(ok)
+	if req.Origin == "" {
+		return fmt.Errorf("%w: origin is required", entity.ErrInvalidRequest)
+	}
+	return nil
+}
+
+func (s *stubFlightService) SearchFlight(ctx context.Context, req entity.SearchRequest) (entity.SearchResponse, error) {
+	if err := s.validate(ctx, req); err != nil {
+		return entity.SearchResponse{}, err
+	}
+	return entity.SearchResponse{
+		SearchCriteria: entity.SearchCriteria{Origin: req.Origin, Destination: req.Destination},
+		Metadata:       entity.Metadata{TotalResults: 1, ProvidersQueried: 1, ProvidersSucceeded: 1},
+		Flights:        []entity.Flight{stubFlight()},
+	}, nil
+}
+
+func (s *stubFlightService) SearchFlightStream(ctx context.Context, req entity.SearchRequest) (<-chan entity.SearchEvent, error) {
+	if err := s.validate(ctx, req); err != nil {
+		return nil, err
+	}
+	flight := stubFlight()
+	events := make(chan entity.SearchEvent, 3)
+	events <- entity.SearchEvent{Type: entity.EVENT_PROVIDER, Provider: &entity.ProviderResult{
+		Provider: "Garuda",
+		Status:   entity.PROVIDER_OK,
+		Flights:  []entity.Flight{flight},
+	}}
+	events <- entity.SearchEvent{Type: entity.EVENT_BEST_VALUE, BestValue: &flight}
+	events <- entity.SearchEvent{Type: entity.EVENT_SUMMARY, Summary: &entity.SearchSummary{
+		Metadata: entity.Metadata{TotalResults: 1},
+	}}
+	close(events)
+	return events, nil
+}
+
+func (s *stubFlightService) SearchMultiCity(ctx context.Context, req entity.MultiCitySearchRequest) (entity.MultiCitySearchResponse, error) {
+	return entity.MultiCitySearchResponse{}, errors.New("not served over gRPC")
+}
+
+func (s *stubFlightService) SearchFareCalendar(ctx context.Context, req entity.FareCalendarRequest) (entity.FareCalendarResponse, error) {
+	return entity.FareCalendarResponse{}, errors.New("not served over gRPC")
+}
+
+func stubFlight() entity.Flight {
+	return entity.Flight{
+		ID:           "GA400",
+		Provider:     "Garuda",
+		FlightNumber: "GA400",
+		Departure:    entity.LocationDetails{Airport: "CGK", Datetime: time.Date(2025, 12, 15, 6, 0, 0, 0, time.UTC)},
+		Arrival:      entity.LocationDetails{Airport: "DPS", Datetime: time.Date(2025, 12, 15, 8, 50, 0, 0, time.UTC)},
+		Price:        entity.PriceDetails{Amount: 1250000, Currency: "IDR"},
+	}
+}
+
+// dial serves the stub over an in-process bufconn listener
+func dial(t *testing.T) (flightv1.FlightServiceClient, *stubFlightService) {
+	t.Helper()
+
+	stub := &stubFlightService{}
+	return dialService(t, stub), stub
+}
+
+// dialService serves flightService over an in-process bufconn listener
+func dialService(t *testing.T, flightService service.FlightService) flightv1.FlightServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := New(flightService, slog.New(slog.DiscardHandler))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial bufconn: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return flightv1.NewFlightServiceClient(conn)
+}
+
+func TestSearch(t *testing.T) {
+	client, stub := dial(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res, err := client.Search(ctx, &flightv1.SearchRequest{
+		Origin:        "CGK",
+		Destinations:  []string{"DPS"},
+		DepartureDate: "2025-12-15",
+	})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	if !stub.hadDeadline.Load() {
+		t.Error("the call deadline did not reach the flight service")
+	}
+	if got := res.GetSearchCriteria().GetOrigin(); got != "CGK" {
+		t.Errorf("search_criteria.origin = %q, want CGK", got)
+	}
+	if got := res.GetMetadata().GetTotalResults(); got != 1 {
+		t.Errorf("metadata.total_results = %d, want 1", got)
+	}
+	if len(res.GetFlights()) != 1 || res.GetFlights()[0].GetFlightNumber() != "GA400" {
+		t.Errorf("flights = %v, want GA400", res.GetFlights())
+	}
+}
+
+func TestSearchStream(t *testing.T) {
+	client, _ := dial(t)
+
+	stream, err := client.SearchStream(context.Background(), &flightv1.SearchRequest{
+		Origin:        "CGK",
+		Destinations:  []string{"DPS"},
+		DepartureDate: "2025-12-15",
+	})
+	if err != nil {
+		t.Fatalf("SearchStream: %v", err)
+	}
+
+	var kinds []string
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		switch event.GetEvent().(type) {
+		case *flightv1.SearchEvent_Provider:
+			kinds = append(kinds, entity.EVENT_PROVIDER)
+			if got := event.GetProvider().GetProvider(); got != "Garuda" {
+				t.Errorf("provider = %q, want Garuda", got)
+			}
+		case *flightv1.SearchEvent_BestValue:
+			kinds = append(kinds, entity.EVENT_BEST_VALUE)
+		case *flightv1.SearchEvent_Summary:
+			kinds = append(kinds, entity.EVENT_SUMMARY)
+		}
+	}
+
+	want := []string{entity.EVENT_PROVIDER, entity.EVENT_BEST_VALUE, entity.EVENT_SUMMARY}
+	if fmt.Sprint(kinds) != fmt.Sprint(want) {
+		t.Errorf("events = %v, want %v", kinds, want)
+	}
+}
+
+func TestInvalidArgument(t *testing.T) {
+	client, _ := dial(t)
+	req := &flightv1.SearchRequest{Destinations: []string{"DPS"}, DepartureDate: "2025-12-15"}
+
+	_, err := client.Search(context.Background(), req)
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Errorf("Search code = %v, want InvalidArgument (%v)", code, err)
+	}
+
+	stream, err := client.SearchStream(context.Background(), req)
+	if err != nil {
+		t.Fatalf("SearchStream: %v", err)
+	}
+	_, err = stream.Recv()
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Errorf("SearchStream code = %v, want InvalidArgument (%v)", code, err)
+	}
+}
+
+// slowProvider records the context of its calls and answers when release is closed
+type slowProvider struct {
+	deadlines chan time.Time
+	cancelled chan error
+	release   chan struct{}
+}
+
+func (p *slowProvider) Code() string { return "Garuda" }
+func (p *slowProvider) Name() string { return "Garuda Indonesia" }
+
+func (p *slowProvider) GetFlight(ctx context.Context, query provider.Query) ([]entity.Flight, error) {
+	deadline, _ := ctx.Deadline()
+	p.deadlines <- deadline
+
+	select {
+	case <-p.release:
+		return []entity.Flight{stubFlight()}, nil
+	case <-ctx.Done():
+		p.cancelled <- ctx.Err()
+		return nil, ctx.Err()
+	}
+}
+
+// emptyCache misses every lookup
+type emptyCache struct{}
+
+func (emptyCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return nil
+}
+func (emptyCache) Get(ctx context.Context, key string, target interface{}) error {
+	return redis.ErrKeyNotFound
+}
+func (emptyCache) Delete(ctx context.Context, key string) error { return nil }
+
+// oneCurrency only knows IDR
+type oneCurrency struct{}
+
+func (oneCurrency) Rate(from, to string) (float64, error) { return 1, nil }
+func (oneCurrency) Supports(currency string) bool         { return currency == "IDR" }
+
+func TestSearchDeadlineReachesProviders(t *testing.T) {
+	slow := &slowProvider{
+		deadlines: make(chan time.Time, 1),
+		cancelled: make(chan error, 1),
+		release:   make(chan struct{}),
+	}
+	providers, err := provider.NewRegistry(slow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := service.DefaultConfig()
+	config.SearchDeadline = 0
+	flightService := service.NewFlightService(providers, emptyCache{}, oneCurrency{}, config, slog.New(slog.DiscardHandler))
+	client := dialService(t, flightService)
+
+	// well inside the provider budget, so the call deadline is the one that counts
+	callDeadline := time.Now().Add(300 * time.Millisecond)
+	ctx, cancel := context.WithDeadline(context.Background(), callDeadline)
+	defer cancel()
+	res, err := client.Search(ctx, &flightv1.SearchRequest{
+		Origin:        "CGK",
+		Destinations:  []string{"DPS"},
+		DepartureDate: "2025-12-15",
+		Passengers:    1,
+	})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	providerDeadline := <-slow.deadlines
+	if providerDeadline.IsZero() {
+		t.Fatal("the provider call had no deadline")
+	}
+	if providerDeadline.After(callDeadline) {
+		t.Errorf("provider deadline %v is after the call deadline %v", providerDeadline, callDeadline)
+	}
+
+	// the provider never answered: it is reported late and its call is cancelled
+	if got := res.GetMetadata().GetProvidersTimedOut(); len(got) != 1 || got[0] != "Garuda" {
+		t.Errorf("metadata.providers_timed_out = %v, want [Garuda]", got)
+	}
+	select {
+	case err := <-slow.cancelled:
+		if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled) {
+			t.Errorf("provider context err = %v, want it cancelled", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("the provider call outlived the search")
+	}
+}
@@ -0,0 +1,264 @@
+package redis
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type LayeredConfig struct {
+	// Capacity is the number of keys kept in memory, the least recently used is evicted first
+	Capacity int
+	// TTL caps how long a key read from redis stays in memory (redis does not tell us its TTL)
+	TTL time.Duration
+	// RetryAfter is how long redis is left alone after it failed
+	RetryAfter time.Duration
+	// Timeout bounds every redis call, so a redis that hangs or refuses connections
+	// costs a search at most this long instead of the client's dial retries
+	Timeout time.Duration
+}
+
+func DefaultLayeredConfig() LayeredConfig {
+	return LayeredConfig{
+		Capacity:   1000,
+		TTL:        30 * time.Second,
+		RetryAfter: 10 * time.Second,
+		Timeout:    200 * time.Millisecond,
+	}
+}
+
+type CacheStats struct {
+	L1Hits      uint64 `json:"l1_hits"`
+	L1Misses    uint64 `json:"l1_misses"`
+	L1Evictions uint64 `json:"l1_evictions"`
+	L1Size      int    `json:"l1_size"`
+	L2Hits      uint64 `json:"l2_hits"`
+	L2Misses    uint64 `json:"l2_misses"`
+	L2Errors    uint64 `json:"l2_errors"`
+	Healthy     bool   `json:"redis_healthy"`
+}
+
+// HealthChecker is implemented by caches that can tell whether their backend is reachable
+type HealthChecker interface {
+	Healthy() bool
+}
+
+type l1Entry struct {
+	key       string
+	data      []byte
+	expiresAt time.Time
+}
+
+type layeredCache struct {
+	l2     RedisService
+	config LayeredConfig
+
+	mu    sync.Mutex
+	items map[string]*list.Element
+	order *list.List // front is the most recently used
+
+	// healthy is the outcome of the last redis call that got an answer or failed on its own
+	healthy atomic.Bool
+	// unix nano until which redis is left alone after a failure, 0 when it is called
+	retryAt atomic.Int64
+
+	l1Hits, l1Misses, l1Evictions atomic.Uint64
+	l2Hits, l2Misses, l2Errors    atomic.Uint64
+}
+
+// LayeredCache is an in-memory LRU in front of redis. When redis is unreachable it
+// keeps serving and storing in memory, and retries redis after RetryAfter.
+type LayeredCache interface {
+	RedisService
+	HealthChecker
+	Stats() CacheStats
+}
+
+func NewLayeredCache(l2 RedisService, config LayeredConfig) LayeredCache {
+	if config.Capacity <= 0 {
+		config.Capacity = 1
+	}
+
+	c := &layeredCache{
+		l2:     l2,
+		config: config,
+		items:  make(map[string]*list.Element, config.Capacity),
+		order:  list.New(),
+	}
+	// assumed reachable until the first call says otherwise
+	c.healthy.Store(true)
+	return c
+}
+
+func (c *layeredCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("layeredCache.Set: failed to marshal: %w", err)
+	}
+	c.store(key, data, ttl)
+
+	if !c.callable() {
+		return nil
+	}
+	l2Ctx, cancel := c.l2Context(ctx)
+	defer cancel()
+	// already marshalled, store the raw JSON so redis holds the same bytes
+	err = c.l2.Set(l2Ctx, key, json.RawMessage(data), ttl)
+	c.observe(ctx, err)
+	if err != nil {
+		return fmt.Errorf("layeredCache.Set: %w", err)
+	}
+	return nil
+}
+
+func (c *layeredCache) Get(ctx context.Context, key string, target interface{}) error {
+	if data, ok := c.load(key); ok {
+		c.l1Hits.Add(1)
+		return json.Unmarshal(data, target)
+	}
+	c.l1Misses.Add(1)
+
+	if !c.callable() {
+		return ErrKeyNotFound
+	}
+
+	l2Ctx, cancel := c.l2Context(ctx)
+	defer cancel()
+	var raw json.RawMessage
+	err := c.l2.Get(l2Ctx, key, &raw)
+	c.observe(ctx, err)
+	if errors.Is(err, ErrKeyNotFound) {
+		c.l2Misses.Add(1)
+		return ErrKeyNotFound
+	}
+	if err != nil {
+		return ErrKeyNotFound
+	}
+	c.l2Hits.Add(1)
+
+	c.store(key, raw, c.config.TTL)
+	return json.Unmarshal(raw, target)
+}
+
+func (c *layeredCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	if el, ok := c.items[key]; ok {
+		c.order.Remove(el)
+		delete(c.items, key)
+	}
+	c.mu.Unlock()
+
+	if !c.callable() {
+		return nil
+	}
+	l2Ctx, cancel := c.l2Context(ctx)
+	defer cancel()
+	err := c.l2.Delete(l2Ctx, key)
+	c.observe(ctx, err)
+	if err != nil {
+		return fmt.Errorf("layeredCache.Delete: %w", err)
+	}
+	return nil
+}
+
+// Healthy reports the outcome of the last real redis call, a redis waiting out
+// RetryAfter stays unhealthy until a call after it succeeds
+func (c *layeredCache) Healthy() bool {
+	return c.healthy.Load()
+}
+
+func (c *layeredCache) Stats() CacheStats {
+	c.mu.Lock()
+	size := c.order.Len()
+	c.mu.Unlock()
+
+	return CacheStats{
+		L1Hits:      c.l1Hits.Load(),
+		L1Misses:    c.l1Misses.Load(),
+		L1Evictions: c.l1Evictions.Load(),
+		L1Size:      size,
+		L2Hits:      c.l2Hits.Load(),
+		L2Misses:    c.l2Misses.Load(),
+		L2Errors:    c.l2Errors.Load(),
+		Healthy:     c.Healthy(),
+	}
+}
+
+// callable is false while redis is left alone after a failure
+func (c *layeredCache) callable() bool {
+	retryAt := c.retryAt.Load()
+	return retryAt == 0 || time.Now().UnixNano() >= retryAt
+}
+
+// l2Context bounds one redis call by config.Timeout, on top of the caller's own deadline
+func (c *layeredCache) l2Context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.config.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.config.Timeout)
+}
+
+// observe records the outcome of a redis call. A call that failed because the caller
+// cancelled or ran out of time says nothing about redis and is ignored.
+func (c *layeredCache) observe(ctx context.Context, err error) {
+	if err == nil || errors.Is(err, ErrKeyNotFound) {
+		c.healthy.Store(true)
+		c.retryAt.Store(0)
+		return
+	}
+	if ctx.Err() != nil {
+		return
+	}
+	c.l2Errors.Add(1)
+	c.healthy.Store(false)
+	c.retryAt.Store(time.Now().Add(c.config.RetryAfter).UnixNano())
+}
+
+func (c *layeredCache) store(key string, data []byte, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = c.config.TTL
+	}
+	entry := &l1Entry{key: key, data: data, expiresAt: time.Now().Add(ttl)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.config.Capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*l1Entry).key)
+		c.l1Evictions.Add(1)
+	}
+}
+
+func (c *layeredCache) load(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := el.Value.(*l1Entry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(el)
+		delete(c.items, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(el)
+	return entry.data, true
+}
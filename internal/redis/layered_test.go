@@ -0,0 +1,279 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis keeps raw JSON like redis does. While down every call fails, while
+// hanging every call waits for its context.
+type fakeRedis struct {
+	mu      sync.Mutex
+	data    map[string][]byte
+	down    bool
+	hanging bool
+	calls   int
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{data: make(map[string][]byte)}
+}
+
+func (r *fakeRedis) call(ctx context.Context) error {
+	r.mu.Lock()
+	r.calls++
+	down, hanging := r.down, r.hanging
+	r.mu.Unlock()
+
+	if hanging {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	if down {
+		return errors.New("dial tcp: connection refused")
+	}
+	return nil
+}
+
+func (r *fakeRedis) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if err := r.call(ctx); err != nil {
+		return err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.data[key] = data
+	return nil
+}
+
+func (r *fakeRedis) Get(ctx context.Context, key string, target interface{}) error {
+	if err := r.call(ctx); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	data, ok := r.data[key]
+	r.mu.Unlock()
+	if !ok {
+		return ErrKeyNotFound
+	}
+	return json.Unmarshal(data, target)
+}
+
+func (r *fakeRedis) Delete(ctx context.Context, key string) error {
+	if err := r.call(ctx); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.data, key)
+	return nil
+}
+
+func (r *fakeRedis) set(down, hanging bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.down, r.hanging = down, hanging
+}
+
+func (r *fakeRedis) callCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls
+}
+
+func TestLayeredCacheLRU(t *testing.T) {
+	l2 := newFakeRedis()
+	c := NewLayeredCache(l2, LayeredConfig{Capacity: 2, TTL: time.Minute})
+	ctx := context.Background()
+
+	for _, key := range []string{"a", "b"} {
+		if err := c.Set(ctx, key, key+"-value", time.Minute); err != nil {
+			t.Fatalf("Set(%s): %v", key, err)
+		}
+	}
+	// a is used, so b is the least recently used when c comes in
+	var got string
+	if err := c.Get(ctx, "a", &got); err != nil || got != "a-value" {
+		t.Fatalf("Get(a) = %q, %v", got, err)
+	}
+	if err := c.Set(ctx, "c", "c-value", time.Minute); err != nil {
+		t.Fatalf("Set(c): %v", err)
+	}
+
+	stats := c.Stats()
+	if stats.L1Size != 2 || stats.L1Evictions != 1 || stats.L1Hits != 1 {
+		t.Fatalf("stats = %+v, want 2 in memory, 1 eviction, 1 hit", stats)
+	}
+
+	// b left memory but not redis, reading it brings it back
+	calls := l2.callCount()
+	if err := c.Get(ctx, "b", &got); err != nil || got != "b-value" {
+		t.Fatalf("Get(b) = %q, %v", got, err)
+	}
+	if l2.callCount() != calls+1 || c.Stats().L2Hits != 1 {
+		t.Fatalf("Get(b) did not read redis once: %d calls, stats %+v", l2.callCount()-calls, c.Stats())
+	}
+	if err := c.Get(ctx, "b", &got); err != nil || l2.callCount() != calls+1 {
+		t.Fatalf("second Get(b) = %v with %d redis calls, want a memory hit", err, l2.callCount()-calls)
+	}
+
+	if err := c.Get(ctx, "nope", &got); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("Get(nope) = %v, want ErrKeyNotFound", err)
+	}
+}
+
+func TestLayeredCacheMemoryTTL(t *testing.T) {
+	l2 := newFakeRedis()
+	c := NewLayeredCache(l2, LayeredConfig{Capacity: 10, TTL: time.Minute}).(*layeredCache)
+	ctx := context.Background()
+
+	if err := c.Set(ctx, "k", "v", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	// the memory copy expired, redis still has it
+	c.items["k"].Value.(*l1Entry).expiresAt = time.Now().Add(-time.Second)
+
+	var got string
+	calls := l2.callCount()
+	if err := c.Get(ctx, "k", &got); err != nil || got != "v" {
+		t.Fatalf("Get = %q, %v", got, err)
+	}
+	if l2.callCount() != calls+1 {
+		t.Fatalf("an expired memory entry was served without asking redis")
+	}
+}
+
+func TestLayeredCacheRedisHealth(t *testing.T) {
+	// events: down, up and hang change redis, get and set use the cache, cancelled
+	// gets with a context that is already done, wait lets RetryAfter pass. After
+	// each one the health and the number of redis calls made by it are checked.
+	type step struct {
+		event       string
+		wantHealthy bool
+		wantCalls   int
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "healthy while redis answers, a miss included",
+			steps: []step{
+				{"get", true, 1},
+				{"set", true, 1},
+				{"get", true, 0},
+			},
+		},
+		{
+			name: "a failed call marks it unhealthy",
+			steps: []step{
+				{"down", true, 0},
+				{"get", false, 1},
+			},
+		},
+		{
+			name: "redis is left alone for RetryAfter, memory keeps working",
+			steps: []step{
+				{"down", true, 0},
+				{"get", false, 1},
+				{"get", false, 0},
+				{"set", false, 0},
+				{"get", false, 0},
+			},
+		},
+		{
+			name: "retried after RetryAfter",
+			steps: []step{
+				{"down", true, 0},
+				{"get", false, 1},
+				{"up", false, 0},
+				{"get", false, 0},
+				{"wait", false, 0},
+				{"get", true, 1},
+			},
+		},
+		{
+			name: "a failed retry waits again",
+			steps: []step{
+				{"down", true, 0},
+				{"get", false, 1},
+				{"wait", false, 0},
+				{"get", false, 1},
+				{"get", false, 0},
+			},
+		},
+		{
+			name: "a caller that gave up says nothing about redis",
+			steps: []step{
+				{"down", true, 0},
+				{"cancelled", true, 1},
+				{"get", false, 1},
+			},
+		},
+		{
+			name: "Timeout cuts a hanging redis and counts as a failure",
+			steps: []step{
+				{"hang", true, 0},
+				{"get", false, 1},
+				{"get", false, 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l2 := newFakeRedis()
+			c := NewLayeredCache(l2, LayeredConfig{
+				Capacity:   10,
+				TTL:        time.Minute,
+				RetryAfter: 10 * time.Second,
+				Timeout:    20 * time.Millisecond,
+			}).(*layeredCache)
+
+			for i, s := range tt.steps {
+				calls := l2.callCount()
+				ctx := context.Background()
+				var value string
+
+				switch s.event {
+				case "down":
+					l2.set(true, false)
+				case "up":
+					l2.set(false, false)
+				case "hang":
+					l2.set(false, true)
+				case "wait":
+					c.retryAt.Store(time.Now().Add(-time.Second).UnixNano())
+				case "get":
+					// only a value set through the cache is ever found
+					if err := c.Get(ctx, "key", &value); err != nil && !errors.Is(err, ErrKeyNotFound) {
+						t.Fatalf("step %d: Get = %v, want a value or ErrKeyNotFound", i, err)
+					}
+				case "cancelled":
+					cancelled, cancel := context.WithCancel(ctx)
+					cancel()
+					c.Get(cancelled, "key", &value)
+				case "set":
+					// memory keeps it whatever redis says
+					c.Set(ctx, "key", fmt.Sprintf("value %d", i), time.Minute)
+				}
+
+				if got := c.Healthy(); got != s.wantHealthy {
+					t.Fatalf("step %d (%s): healthy = %v, want %v", i, s.event, got, s.wantHealthy)
+				}
+				if got := l2.callCount() - calls; got != s.wantCalls {
+					t.Fatalf("step %d (%s): redis calls = %d, want %d", i, s.event, got, s.wantCalls)
+				}
+			}
+		})
+	}
+}
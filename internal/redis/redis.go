@@ -3,18 +3,36 @@ package redis
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// ErrKeyNotFound is returned by Get on a cache miss, any other error means the cache itself failed
+var ErrKeyNotFound = errors.New("key does not exist")
+
 type RedisService interface {
 	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
 	Get(ctx context.Context, key string, target interface{}) error
 	Delete(ctx context.Context, key string) error
 }
 
+// SetLogger routes the messages of the redis client itself, such as dial failures, to log
+func SetLogger(log *slog.Logger) {
+	redis.SetLogger(clientLogger{log})
+}
+
+type clientLogger struct {
+	log *slog.Logger
+}
+
+func (l clientLogger) Printf(ctx context.Context, format string, v ...interface{}) {
+	l.log.WarnContext(ctx, fmt.Sprintf(format, v...), "component", "redis")
+}
+
 type redisService struct {
 	client *redis.Client
 }
@@ -43,7 +61,7 @@ func (r *redisService) Set(ctx context.Context, key string, value interface{}, t
 func (r *redisService) Get(ctx context.Context, key string, target interface{}) error {
 	val, err := r.client.Get(ctx, key).Result()
 	if err == redis.Nil {
-		return fmt.Errorf("key does not exist")
+		return ErrKeyNotFound
 	} else if err != nil {
 		return fmt.Errorf("redis.Get: %w", err)
 	}
@@ -3,12 +3,12 @@ package airasia
 import (
 	"context"
 	"encoding/json"
-	logger "flight-aggregator/internal/common"
 	"flight-aggregator/internal/common/util"
 	"flight-aggregator/internal/entity"
+	"flight-aggregator/internal/service/provider"
 	"fmt"
+	"log/slog"
 	"math/rand"
-	"os"
 	"strings"
 	"time"
 )
@@ -18,23 +18,54 @@ type AirAsiaResponse struct {
 	Flights []entity.AirAsiaFlight `json:"flights"`
 }
 
-type airAsiaService struct {
-	filePath string
+// Code and Name identify this airline in the provider registry
+const (
+	Code = "AirAsia"
+	Name = "Air ASIA"
+)
+
+func init() {
+	provider.Register(Code, NewAirAsiaService)
+}
+
+// AirAsia only quotes price_idr, the service converts it for other display currencies
+const currency = "IDR"
+
+// cabinClasses maps AirAsia cabin_class values
+var cabinClasses = map[string]string{
+	"ECONOMY":         entity.CABIN_ECONOMY,
+	"PREMIUM_FLEX":    entity.CABIN_ECONOMY,
+	"PREMIUM_FLATBED": entity.CABIN_BUSINESS,
 }
 
-type AirAsiaService interface {
-	GetFlight(ctx context.Context) ([]entity.Flight, error)
+type airAsiaService struct {
+	fixtureDir string
+	timeout    time.Duration
+	airports   entity.AirportLookup
+	logger     *slog.Logger
 }
 
-func NewAirAsiaService(path string) AirAsiaService {
+// NewAirAsiaService reads mock responses from opts.FixtureDir, one file per route and date.
+// Airport names, cities and timezones come from opts.Airports.
+func NewAirAsiaService(opts provider.Options) provider.Provider {
 	return &airAsiaService{
-		filePath: path,
+		fixtureDir: opts.FixtureDir,
+		timeout:    opts.TimeoutOrDefault(),
+		airports:   opts.Airports,
+		logger:     opts.LoggerOrDefault().With("provider", Code),
 	}
 }
 
-// assuming it had 15 December as mock param
-func (a *airAsiaService) GetFlight(ctx context.Context) ([]entity.Flight, error) {
-	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+func (a *airAsiaService) Code() string {
+	return Code
+}
+
+func (a *airAsiaService) Name() string {
+	return Name
+}
+
+func (a *airAsiaService) GetFlight(ctx context.Context, query provider.Query) ([]entity.Flight, error) {
+	ctx, cancel := context.WithTimeoutCause(ctx, a.timeout, fmt.Errorf("AirAsia fetch timed out after %s", a.timeout))
 	defer cancel()
 
 	type result struct {
@@ -54,19 +85,23 @@ func (a *airAsiaService) GetFlight(ctx context.Context) ([]entity.Flight, error)
 			return
 		}
 
-		data, err := os.ReadFile(a.filePath)
+		files, err := provider.ReadFixtures(a.fixtureDir, query)
 		if err != nil {
 			resChan <- result{nil, fmt.Errorf("AirAsia.getFlight: Failed to read file")}
 			return
 		}
 
-		var airAsiaResponse AirAsiaResponse
-		if err := json.Unmarshal(data, &airAsiaResponse); err != nil {
-			resChan <- result{nil, err}
-			return
+		var rawFlights []entity.AirAsiaFlight
+		for _, data := range files {
+			var airAsiaResponse AirAsiaResponse
+			if err := json.Unmarshal(data, &airAsiaResponse); err != nil {
+				resChan <- result{nil, err}
+				return
+			}
+			rawFlights = append(rawFlights, airAsiaResponse.Flights...)
 		}
 
-		flights, err := a.mapFlights(airAsiaResponse.Flights)
+		flights, err := a.mapFlights(ctx, rawFlights)
 		resChan <- result{flights, err}
 	}()
 
@@ -74,12 +109,11 @@ func (a *airAsiaService) GetFlight(ctx context.Context) ([]entity.Flight, error)
 	case res := <-resChan:
 		return res.flights, res.err
 	case <-ctx.Done():
-		return nil, fmt.Errorf("AirAsia fetch timed out after 2s")
+		return nil, context.Cause(ctx)
 	}
 }
 
-func (a *airAsiaService) mapFlights(rawFlights []entity.AirAsiaFlight) ([]entity.Flight, error) {
-	log := logger.Init()
+func (a *airAsiaService) mapFlights(ctx context.Context, rawFlights []entity.AirAsiaFlight) ([]entity.Flight, error) {
 	if len(rawFlights) == 0 {
 		return []entity.Flight{}, nil
 	}
@@ -87,13 +121,13 @@ func (a *airAsiaService) mapFlights(rawFlights []entity.AirAsiaFlight) ([]entity
 	unifiedFlights := make([]entity.Flight, 0, len(rawFlights))
 	for _, raw := range rawFlights {
 		if err := raw.Validate(); err != nil {
-			log.Errorf("Validation failed for AirAsia flight: %v", err)
+			a.logger.WarnContext(ctx, "dropping flight that fails validation", "flight", raw.FlightCode, "error", err)
 			continue
 		}
 
 		unified, err := a.mapFlight(raw)
 		if err != nil {
-			log.Errorf("Error AirAsia.mapFlights: Corrupted data for flight %s", raw.FlightCode)
+			a.logger.WarnContext(ctx, "dropping flight that can not be mapped", "flight", raw.FlightCode, "error", err)
 			continue
 		}
 		unifiedFlights = append(unifiedFlights, unified)
@@ -112,6 +146,13 @@ func (a *airAsiaService) mapFlight(flight entity.AirAsiaFlight) (entity.Flight,
 		return entity.Flight{}, err
 	}
 
+	// Initialize Location Registry
+	lr := entity.NewLocationRegistry(a.airports)
+
+	// keep the times in the airport's own zone
+	depTime = lr.InLocalTime(flight.FromAirport, depTime)
+	arrTime = lr.InLocalTime(flight.ToAirport, arrTime)
+
 	// Convert duration_hours (float64) to minutes
 	elapsedDuration := arrTime.Sub(depTime)
 	totalMinutes := int(elapsedDuration.Minutes())
@@ -119,12 +160,20 @@ func (a *airAsiaService) mapFlight(flight entity.AirAsiaFlight) (entity.Flight,
 	mins := totalMinutes % 60
 	formattedDuration := fmt.Sprintf("%dh %dm", hours, mins)
 
-	// Initialize Location Registry
-	lr := entity.LocationRegistry{}
-
 	// Handle Stops count
 	stopCount := len(flight.Stops)
 
+	// Handle Layovers, AirAsia only sends the airport and the wait time
+	layovers := make([]entity.Layover, 0, len(flight.Stops))
+	for _, stop := range flight.Stops {
+		layovers = append(layovers, entity.Layover{
+			Airport:         stop.Airport,
+			AirportName:     lr.GetAirport(stop.Airport),
+			City:            lr.GetCity(stop.Airport),
+			DurationMinutes: stop.WaitTimeMinutes,
+		})
+	}
+
 	// Handle Baggage
 	baggage := entity.BaggageDetails{
 		CarryOn: "No information",
@@ -139,8 +188,8 @@ func (a *airAsiaService) mapFlight(flight entity.AirAsiaFlight) (entity.Flight,
 	}
 
 	return entity.Flight{
-		ID:           fmt.Sprintf("%s_%s", flight.FlightCode, entity.AIRASIA),
-		Provider:     entity.PROVIDER_AIR_ASIA,
+		ID:           fmt.Sprintf("%s_%s", flight.FlightCode, Code),
+		Provider:     Name,
 		FlightNumber: flight.FlightCode,
 		Airline: entity.AirlineInfo{
 			Name: flight.Airline,
@@ -151,6 +200,7 @@ func (a *airAsiaService) mapFlight(flight entity.AirAsiaFlight) (entity.Flight,
 			City:      lr.GetCity(flight.FromAirport),
 			Datetime:  depTime,
 			Timestamp: depTime.Unix(),
+			Timezone:  lr.GetTimezone(flight.FromAirport),
 			Code:      flight.FromAirport,
 		},
 		Arrival: entity.LocationDetails{
@@ -158,20 +208,24 @@ func (a *airAsiaService) mapFlight(flight entity.AirAsiaFlight) (entity.Flight,
 			City:      lr.GetCity(flight.ToAirport),
 			Datetime:  arrTime,
 			Timestamp: arrTime.Unix(),
+			Timezone:  lr.GetTimezone(flight.ToAirport),
 			Code:      flight.ToAirport,
 		},
 		Duration: entity.DurationDetails{
 			TotalMinutes: totalMinutes,
 			Formatted:    formattedDuration,
 		},
-		Stops: stopCount,
+		Stops:    stopCount,
+		Layovers: layovers,
 		Price: entity.PriceDetails{
 			Amount:    flight.PriceIDR,
-			Currency:  "IDR",
-			Formatted: util.FormatIDR(flight.PriceIDR),
+			Currency:  currency,
+			Formatted: util.FormatMoney(flight.PriceIDR, currency),
+			Breakdown: entity.TotalOnlyFare(),
 		},
 		AvailableSeats: flight.Seats,
-		CabinClass:     flight.CabinClass,
+		CabinClass:     entity.MapCabinClass(cabinClasses, flight.CabinClass),
+		FareClass:      flight.CabinClass,
 		Aircraft:       nil,
 		Baggage:        baggage,
 		Amenities:      []string{},
@@ -0,0 +1,145 @@
+package airasia
+
+import (
+	"flight-aggregator/internal/entity"
+	"flight-aggregator/internal/service/provider"
+	"log/slog"
+	"slices"
+	"testing"
+)
+
+// airports is a fixed AirportLookup, KOE is left out on purpose
+type airports map[string]entity.Airport
+
+func (a airports) Get(code string) (entity.Airport, bool) {
+	airport, ok := a[code]
+	return airport, ok
+}
+
+func newTestService() *airAsiaService {
+	return NewAirAsiaService(provider.Options{
+		Airports: airports{
+			"CGK": {Code: "CGK", Name: "Soekarno-Hatta International", City: "Jakarta", Timezone: "Asia/Jakarta"},
+			"DPS": {Code: "DPS", Name: "I Gusti Ngurah Rai International", City: "Denpasar", Timezone: "Asia/Makassar"},
+			"SUB": {Code: "SUB", Name: "Juanda International", City: "Surabaya", Timezone: "Asia/Jakarta"},
+		},
+		Logger: slog.New(slog.DiscardHandler),
+	}).(*airAsiaService)
+}
+
+func testFlight() entity.AirAsiaFlight {
+	return entity.AirAsiaFlight{
+		FlightCode:  "QZ7510",
+		Airline:     "AirAsia",
+		FromAirport: "CGK",
+		ToAirport:   "DPS",
+		DepartTime:  "2025-12-15T06:00:00+07:00",
+		ArriveTime:  "2025-12-15T11:30:00+08:00",
+		PriceIDR:    650000,
+		Seats:       40,
+		CabinClass:  "ECONOMY",
+		BaggageNote: "Cabin baggage only, checked bags additional fee",
+	}
+}
+
+func TestMapFlightCabinClass(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"ECONOMY", entity.CABIN_ECONOMY},
+		{"PREMIUM_FLEX", entity.CABIN_ECONOMY},
+		{"premium_flatbed", entity.CABIN_BUSINESS},
+		{" Business ", entity.CABIN_BUSINESS},
+		{"HOT_SEAT", entity.CABIN_UNKNOWN},
+		{"", entity.CABIN_UNKNOWN},
+	}
+
+	a := newTestService()
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			raw := testFlight()
+			raw.CabinClass = tt.raw
+
+			fl, err := a.mapFlight(raw)
+			if err != nil {
+				t.Fatalf("mapFlight: %v", err)
+			}
+			if fl.CabinClass != tt.want || fl.FareClass != tt.raw {
+				t.Errorf("cabin = %q, fare class %q, want %q and the raw %q", fl.CabinClass, fl.FareClass, tt.want, tt.raw)
+			}
+		})
+	}
+}
+
+func TestMapFlightLayovers(t *testing.T) {
+	tests := []struct {
+		name  string
+		stops []entity.AirAsiaStop
+		want  []entity.Layover
+	}{
+		{"direct", nil, []entity.Layover{}},
+		{
+			name:  "one stop",
+			stops: []entity.AirAsiaStop{{Airport: "SUB", WaitTimeMinutes: 95}},
+			want:  []entity.Layover{{Airport: "SUB", AirportName: "Juanda International", City: "Surabaya", DurationMinutes: 95}},
+		},
+		{
+			name:  "an airport missing from the registry keeps its code",
+			stops: []entity.AirAsiaStop{{Airport: "SUB", WaitTimeMinutes: 60}, {Airport: "KOE", WaitTimeMinutes: 45}},
+			want: []entity.Layover{
+				{Airport: "SUB", AirportName: "Juanda International", City: "Surabaya", DurationMinutes: 60},
+				{Airport: "KOE", DurationMinutes: 45},
+			},
+		},
+	}
+
+	a := newTestService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := testFlight()
+			raw.Stops = tt.stops
+
+			fl, err := a.mapFlight(raw)
+			if err != nil {
+				t.Fatalf("mapFlight: %v", err)
+			}
+			if fl.Stops != len(tt.want) {
+				t.Errorf("stops = %d, want %d", fl.Stops, len(tt.want))
+			}
+			if !slices.Equal(fl.Layovers, tt.want) {
+				t.Errorf("layovers = %+v, want %+v", fl.Layovers, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapFlightLocalTimes(t *testing.T) {
+	fl, err := newTestService().mapFlight(testFlight())
+	if err != nil {
+		t.Fatalf("mapFlight: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		got      entity.LocationDetails
+		airport  string
+		city     string
+		timezone string
+		clock    string
+	}{
+		{"departure", fl.Departure, "Soekarno-Hatta International", "Jakarta", "Asia/Jakarta", "06:00"},
+		{"arrival", fl.Arrival, "I Gusti Ngurah Rai International", "Denpasar", "Asia/Makassar", "11:30"},
+	}
+	for _, tt := range tests {
+		if tt.got.Airport != tt.airport || tt.got.City != tt.city || tt.got.Timezone != tt.timezone {
+			t.Errorf("%s = %s, %s, %s, want %s, %s, %s", tt.name, tt.got.Airport, tt.got.City, tt.got.Timezone, tt.airport, tt.city, tt.timezone)
+		}
+		if got := tt.got.Datetime.Format("15:04"); got != tt.clock || tt.got.Datetime.Location().String() != tt.timezone {
+			t.Errorf("%s time = %s in %s, want %s local", tt.name, got, tt.got.Datetime.Location(), tt.clock)
+		}
+	}
+	if fl.Duration.TotalMinutes != 270 || fl.Duration.Formatted != "4h 30m" {
+		t.Errorf("duration = %d (%s), want 270 (4h 30m)", fl.Duration.TotalMinutes, fl.Duration.Formatted)
+	}
+}
@@ -0,0 +1,119 @@
+package airport
+
+import (
+	"encoding/json"
+	"flight-aggregator/internal/entity"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Registry serves the airport dataset. Lookups never block a reload, they keep
+// reading the previous snapshot until the new one is in place.
+type Registry interface {
+	Get(code string) (entity.Airport, bool)
+	// FindByCity returns every airport of the city, case insensitive, sorted by code
+	FindByCity(city string) []entity.Airport
+	Len() int
+	// Reload reads the dataset again, the current data stays when the file is invalid
+	Reload() error
+}
+
+type snapshot struct {
+	byCode map[string]entity.Airport
+	byCity map[string][]entity.Airport
+}
+
+type registry struct {
+	path string
+	data atomic.Pointer[snapshot]
+}
+
+// Load reads the JSON dataset at path, a list of entity.Airport
+func Load(path string) (Registry, error) {
+	r := &registry{path: path}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *registry) Get(code string) (entity.Airport, bool) {
+	airport, ok := r.data.Load().byCode[strings.ToUpper(code)]
+	return airport, ok
+}
+
+func (r *registry) FindByCity(city string) []entity.Airport {
+	return slices.Clone(r.data.Load().byCity[cityKey(city)])
+}
+
+func (r *registry) Len() int {
+	return len(r.data.Load().byCode)
+}
+
+func (r *registry) Reload() error {
+	raw, err := os.ReadFile(r.path)
+	if err != nil {
+		return fmt.Errorf("read airports %s: %w", r.path, err)
+	}
+
+	var airports []entity.Airport
+	if err := json.Unmarshal(raw, &airports); err != nil {
+		return fmt.Errorf("parse airports %s: %w", r.path, err)
+	}
+
+	data, err := index(airports)
+	if err != nil {
+		return fmt.Errorf("airports %s: %w", r.path, err)
+	}
+
+	r.data.Store(data)
+	return nil
+}
+
+// index validates every airport and builds the code and city lookups
+func index(airports []entity.Airport) (*snapshot, error) {
+	data := &snapshot{
+		byCode: make(map[string]entity.Airport, len(airports)),
+		byCity: make(map[string][]entity.Airport),
+	}
+
+	for i, airport := range airports {
+		airport.Code = strings.ToUpper(strings.TrimSpace(airport.Code))
+		if len(airport.Code) != 3 {
+			return nil, fmt.Errorf("entry %d: code %q must be a 3-letter IATA code", i, airport.Code)
+		}
+		if _, ok := data.byCode[airport.Code]; ok {
+			return nil, fmt.Errorf("entry %d: duplicate code %s", i, airport.Code)
+		}
+		if airport.Name == "" || airport.City == "" {
+			return nil, fmt.Errorf("%s: name and city are required", airport.Code)
+		}
+		if _, err := time.LoadLocation(airport.Timezone); airport.Timezone == "" || err != nil {
+			return nil, fmt.Errorf("%s: invalid timezone %q", airport.Code, airport.Timezone)
+		}
+		if airport.Latitude < -90 || airport.Latitude > 90 || airport.Longitude < -180 || airport.Longitude > 180 {
+			return nil, fmt.Errorf("%s: coordinates out of range", airport.Code)
+		}
+
+		data.byCode[airport.Code] = airport
+		key := cityKey(airport.City)
+		data.byCity[key] = append(data.byCity[key], airport)
+	}
+
+	for _, cityAirports := range data.byCity {
+		sort.Slice(cityAirports, func(i, j int) bool {
+			return cityAirports[i].Code < cityAirports[j].Code
+		})
+	}
+
+	return data, nil
+}
+
+func cityKey(city string) string {
+	return strings.ToLower(strings.TrimSpace(city))
+}
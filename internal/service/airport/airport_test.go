@@ -0,0 +1,165 @@
+package airport
+
+import (
+	"flight-aggregator/internal/entity"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	cgk = `{"code": "CGK", "name": "Soekarno-Hatta International", "city": "Jakarta", "timezone": "Asia/Jakarta", "latitude": -6.1256, "longitude": 106.6559}`
+	hlp = `{"code": "hlp", "name": "Halim Perdanakusuma International", "city": "Jakarta", "timezone": "Asia/Jakarta", "latitude": -6.2666, "longitude": 106.8911}`
+	dps = `{"code": "DPS", "name": "I Gusti Ngurah Rai International", "city": "Denpasar", "timezone": "Asia/Makassar", "latitude": -8.7482, "longitude": 115.1675}`
+)
+
+// writeAirports writes the entries as the dataset at path
+func writeAirports(t *testing.T, path string, entries ...string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte("["+strings.Join(entries, ",")+"]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func codes(airports []entity.Airport) []string {
+	var result []string
+	for _, a := range airports {
+		result = append(result, a.Code)
+	}
+	return result
+}
+
+func TestRegistryLookups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "airports.json")
+	writeAirports(t, path, cgk, dps, hlp)
+	r, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if r.Len() != 3 {
+		t.Errorf("Len = %d, want 3", r.Len())
+	}
+	for _, code := range []string{"CGK", "cgk", "HLP"} {
+		if _, ok := r.Get(code); !ok {
+			t.Errorf("Get(%s) found nothing", code)
+		}
+	}
+	if a, _ := r.Get("DPS"); a.City != "Denpasar" || a.Timezone != "Asia/Makassar" {
+		t.Errorf("Get(DPS) = %+v", a)
+	}
+	if _, ok := r.Get("SUB"); ok {
+		t.Error("Get(SUB) found an airport that is not in the data")
+	}
+
+	tests := []struct {
+		city string
+		want []string
+	}{
+		{"Jakarta", []string{"CGK", "HLP"}},
+		{" jakarta ", []string{"CGK", "HLP"}},
+		{"Denpasar", []string{"DPS"}},
+		{"Surabaya", nil},
+	}
+	for _, tt := range tests {
+		if got := codes(r.FindByCity(tt.city)); !slices.Equal(got, tt.want) {
+			t.Errorf("FindByCity(%q) = %v, want %v", tt.city, got, tt.want)
+		}
+	}
+}
+
+func TestLoadShippedData(t *testing.T) {
+	r, err := Load(filepath.Join("..", "..", "..", "data", "airports.json"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	for _, code := range []string{"CGK", "DPS", "SUB", "UPG"} {
+		if _, ok := r.Get(code); !ok {
+			t.Errorf("Get(%s) found nothing", code)
+		}
+	}
+}
+
+func TestLoadRejectsInvalidData(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"not json", `{"code": "CGK"`, "parse airports"},
+		{"short code", `[{"code": "CG", "name": "x", "city": "y", "timezone": "Asia/Jakarta"}]`, `code "CG" must be a 3-letter IATA code`},
+		{"duplicate", "[" + cgk + "," + strings.Replace(cgk, "CGK", "cgk", 1) + "]", "duplicate code CGK"},
+		{"no city", `[{"code": "CGK", "name": "x", "timezone": "Asia/Jakarta"}]`, "name and city are required"},
+		{"no timezone", `[{"code": "CGK", "name": "x", "city": "y"}]`, `invalid timezone ""`},
+		{"unknown timezone", `[{"code": "CGK", "name": "x", "city": "y", "timezone": "Asia/Atlantis"}]`, "invalid timezone"},
+		{"latitude out of range", `[{"code": "CGK", "name": "x", "city": "y", "timezone": "Asia/Jakarta", "latitude": 91}]`, "coordinates out of range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "airports.json")
+			if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Load error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "airports.json")
+	writeAirports(t, path, cgk)
+	r, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	// lookups go on while the data is replaced
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	defer func() {
+		close(stop)
+		wg.Wait()
+	}()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				if _, ok := r.Get("CGK"); !ok {
+					t.Error("CGK went missing during a reload")
+					return
+				}
+				r.FindByCity("Jakarta")
+			}
+		}
+	}()
+
+	writeAirports(t, path, cgk, dps)
+	if err := r.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if _, ok := r.Get("DPS"); !ok || r.Len() != 2 {
+		t.Errorf("after a reload Len = %d, DPS found %v, want 2 and true", r.Len(), ok)
+	}
+
+	// an invalid file keeps the current data
+	if err := os.WriteFile(path, []byte(`[{"code": "DP"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reload(); err == nil {
+		t.Error("Reload of an invalid file succeeded")
+	}
+	if _, ok := r.Get("DPS"); !ok || r.Len() != 2 {
+		t.Errorf("after a failed reload Len = %d, DPS found %v, want the previous data", r.Len(), ok)
+	}
+}
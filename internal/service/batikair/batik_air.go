@@ -3,12 +3,12 @@ package batikair
 import (
 	"context"
 	"encoding/json"
-	logger "flight-aggregator/internal/common"
 	"flight-aggregator/internal/common/util"
 	"flight-aggregator/internal/entity"
+	"flight-aggregator/internal/service/provider"
 	"fmt"
+	"log/slog"
 	"math/rand"
-	"os"
 	"strings"
 	"time"
 )
@@ -19,23 +19,64 @@ type BatikAirResponse struct {
 	Results []entity.BatikFlight `json:"results"`
 }
 
-type batikAirService struct {
-	filePath string
+// Code and Name identify this airline in the provider registry
+const (
+	Code = "BatikAir"
+	Name = "Batik Air"
+)
+
+func init() {
+	provider.Register(Code, NewBatikAirService)
+}
+
+// cabinClasses maps Batik Air booking classes (RBD letters) to cabins
+var cabinClasses = map[string]string{
+	"Y": entity.CABIN_ECONOMY,
+	"B": entity.CABIN_ECONOMY,
+	"H": entity.CABIN_ECONOMY,
+	"K": entity.CABIN_ECONOMY,
+	"L": entity.CABIN_ECONOMY,
+	"M": entity.CABIN_ECONOMY,
+	"N": entity.CABIN_ECONOMY,
+	"Q": entity.CABIN_ECONOMY,
+	"T": entity.CABIN_ECONOMY,
+	"V": entity.CABIN_ECONOMY,
+	"W": entity.CABIN_PREMIUM_ECONOMY,
+	"C": entity.CABIN_BUSINESS,
+	"D": entity.CABIN_BUSINESS,
+	"I": entity.CABIN_BUSINESS,
+	"J": entity.CABIN_BUSINESS,
+	"F": entity.CABIN_FIRST,
 }
 
-type BatikAirService interface {
-	GetFlight(ctx context.Context) ([]entity.Flight, error)
+type batikAirService struct {
+	fixtureDir string
+	timeout    time.Duration
+	airports   entity.AirportLookup
+	logger     *slog.Logger
 }
 
-func NewBatikAirService(path string) BatikAirService {
+// NewBatikAirService reads mock responses from opts.FixtureDir, one file per route and date.
+// Airport names, cities and timezones come from opts.Airports.
+func NewBatikAirService(opts provider.Options) provider.Provider {
 	return &batikAirService{
-		filePath: path,
+		fixtureDir: opts.FixtureDir,
+		timeout:    opts.TimeoutOrDefault(),
+		airports:   opts.Airports,
+		logger:     opts.LoggerOrDefault().With("provider", Code),
 	}
 }
 
-// assuming it had 15 December as mock param
-func (b *batikAirService) GetFlight(ctx context.Context) ([]entity.Flight, error) {
-	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+func (b *batikAirService) Code() string {
+	return Code
+}
+
+func (b *batikAirService) Name() string {
+	return Name
+}
+
+func (b *batikAirService) GetFlight(ctx context.Context, query provider.Query) ([]entity.Flight, error) {
+	ctx, cancel := context.WithTimeoutCause(ctx, b.timeout, fmt.Errorf("BatikAir fetch timed out after %s", b.timeout))
 	defer cancel()
 
 	type result struct {
@@ -50,19 +91,23 @@ func (b *batikAirService) GetFlight(ctx context.Context) ([]entity.Flight, error
 		time.Sleep(delay)
 		// time.Sleep(4 * time.Second)
 
-		data, err := os.ReadFile(b.filePath)
+		files, err := provider.ReadFixtures(b.fixtureDir, query)
 		if err != nil {
 			resChan <- result{nil, fmt.Errorf("Batik.getFlight: Failed to read file")}
 			return
 		}
 
-		var batikAirResponse BatikAirResponse
-		if err := json.Unmarshal(data, &batikAirResponse); err != nil {
-			resChan <- result{nil, err}
-			return
+		var rawFlights []entity.BatikFlight
+		for _, data := range files {
+			var batikAirResponse BatikAirResponse
+			if err := json.Unmarshal(data, &batikAirResponse); err != nil {
+				resChan <- result{nil, err}
+				return
+			}
+			rawFlights = append(rawFlights, batikAirResponse.Results...)
 		}
 
-		flights, err := b.mapFlights(batikAirResponse.Results)
+		flights, err := b.mapFlights(ctx, rawFlights)
 		resChan <- result{flights, err}
 	}()
 
@@ -70,12 +115,11 @@ func (b *batikAirService) GetFlight(ctx context.Context) ([]entity.Flight, error
 	case res := <-resChan:
 		return res.flights, res.err
 	case <-ctx.Done():
-		return nil, fmt.Errorf("BatikAir fetch timed out after 2s")
+		return nil, context.Cause(ctx)
 	}
 }
 
-func (b *batikAirService) mapFlights(rawFlights []entity.BatikFlight) ([]entity.Flight, error) {
-	log := logger.Init()
+func (b *batikAirService) mapFlights(ctx context.Context, rawFlights []entity.BatikFlight) ([]entity.Flight, error) {
 	if len(rawFlights) == 0 {
 		return []entity.Flight{}, nil
 	}
@@ -83,13 +127,13 @@ func (b *batikAirService) mapFlights(rawFlights []entity.BatikFlight) ([]entity.
 
 	for _, raw := range rawFlights {
 		if err := raw.Validate(); err != nil {
-			log.Errorf("Batik Data Integrity Error: %v", err)
+			b.logger.WarnContext(ctx, "dropping flight that fails validation", "flight", raw.FlightNumber, "error", err)
 			continue
 		}
 
 		unified, err := b.mapFlight(raw)
 		if err != nil {
-			log.Errorf("Error Lion.mapFlights: Corrupted data")
+			b.logger.WarnContext(ctx, "dropping flight that can not be mapped", "flight", raw.FlightNumber, "error", err)
 			continue
 		}
 		unifiedFlights = append(unifiedFlights, unified)
@@ -109,6 +153,13 @@ func (b *batikAirService) mapFlight(flight entity.BatikFlight) (entity.Flight, e
 		return entity.Flight{}, err
 	}
 
+	// init location registery
+	locationRegistery := entity.NewLocationRegistry(b.airports)
+
+	// keep the times in the airport's own zone
+	depTime = locationRegistery.InLocalTime(flight.Origin, depTime)
+	arrTime = locationRegistery.InLocalTime(flight.Destination, arrTime)
+
 	elapsedDuration := arrTime.Sub(depTime)
 	totalMinutes := int(elapsedDuration.Minutes())
 	hours := totalMinutes / 60
@@ -126,19 +177,20 @@ func (b *batikAirService) mapFlight(flight entity.BatikFlight) (entity.Flight, e
 
 	aircraft := flight.AircraftModel
 
-	// init location registery
-	locationRegistery := entity.LocationRegistry{}
-
-	// formated IDR
-	const idr = "IDR"
-	var formattedPrice string
-	if flight.Fare.CurrencyCode == idr {
-		formattedPrice = util.FormatIDR(float64(flight.Fare.TotalPrice))
+	// layovers, Batik sends the stop duration as text (e.g. "55m")
+	layovers := make([]entity.Layover, 0, len(flight.Connections))
+	for _, connection := range flight.Connections {
+		layovers = append(layovers, entity.Layover{
+			Airport:         connection.StopAirport,
+			AirportName:     locationRegistery.GetAirport(connection.StopAirport),
+			City:            locationRegistery.GetCity(connection.StopAirport),
+			DurationMinutes: util.ParseDurationStringToInt(connection.StopDuration),
+		})
 	}
 
 	return entity.Flight{
-		ID:       fmt.Sprintf("%s_%s", flight.FlightNumber, entity.BATIKAIR),
-		Provider: entity.PROVIDER_BATIK_AIR,
+		ID:       fmt.Sprintf("%s_%s", flight.FlightNumber, Code),
+		Provider: Name,
 		Airline: entity.AirlineInfo{
 			Name: flight.AirlineName,
 			Code: flight.AirlineIATA,
@@ -149,6 +201,7 @@ func (b *batikAirService) mapFlight(flight entity.BatikFlight) (entity.Flight, e
 			City:      locationRegistery.GetCity(flight.Origin),
 			Datetime:  depTime,
 			Timestamp: depTime.Unix(),
+			Timezone:  locationRegistery.GetTimezone(flight.Origin),
 			Code:      flight.Origin,
 		},
 		Arrival: entity.LocationDetails{
@@ -156,6 +209,7 @@ func (b *batikAirService) mapFlight(flight entity.BatikFlight) (entity.Flight, e
 			City:      locationRegistery.GetCity(flight.Destination),
 			Datetime:  arrTime,
 			Timestamp: arrTime.Unix(),
+			Timezone:  locationRegistery.GetTimezone(flight.Destination),
 			Code:      flight.Destination,
 		},
 		Duration: entity.DurationDetails{
@@ -163,13 +217,16 @@ func (b *batikAirService) mapFlight(flight entity.BatikFlight) (entity.Flight, e
 			Formatted:    formattedDuration,
 		},
 		Stops:          flight.NumberOfStops,
+		Layovers:       layovers,
 		AvailableSeats: flight.SeatsAvailable,
-		CabinClass:     flight.Fare.Class,
+		CabinClass:     entity.MapCabinClass(cabinClasses, flight.Fare.Class),
+		FareClass:      flight.Fare.Class,
 		Aircraft:       &aircraft,
 		Price: entity.PriceDetails{
 			Amount:    flight.Fare.TotalPrice,
 			Currency:  flight.Fare.CurrencyCode,
-			Formatted: formattedPrice,
+			Formatted: util.FormatMoney(flight.Fare.TotalPrice, flight.Fare.CurrencyCode),
+			Breakdown: entity.ItemizedFare(flight.Fare.BasePrice, flight.Fare.Taxes, flight.Fare.TotalPrice),
 		},
 		Baggage:   baggage,
 		Amenities: flight.OnboardServices,
@@ -0,0 +1,144 @@
+package batikair
+
+import (
+	"flight-aggregator/internal/entity"
+	"flight-aggregator/internal/service/provider"
+	"log/slog"
+	"slices"
+	"testing"
+)
+
+// airports answers for CGK, DPS and SUB only
+type airports map[string]entity.Airport
+
+func (a airports) Get(code string) (entity.Airport, bool) {
+	airport, ok := a[code]
+	return airport, ok
+}
+
+func newTestService() *batikAirService {
+	return NewBatikAirService(provider.Options{
+		Airports: airports{
+			"CGK": {Code: "CGK", Name: "Soekarno-Hatta International", City: "Jakarta", Timezone: "Asia/Jakarta"},
+			"DPS": {Code: "DPS", Name: "I Gusti Ngurah Rai International", City: "Denpasar", Timezone: "Asia/Makassar"},
+			"SUB": {Code: "SUB", Name: "Juanda International", City: "Surabaya", Timezone: "Asia/Jakarta"},
+		},
+		Logger: slog.New(slog.DiscardHandler),
+	}).(*batikAirService)
+}
+
+func testFlight() entity.BatikFlight {
+	return entity.BatikFlight{
+		FlightNumber:      "ID6514",
+		AirlineName:       "Batik Air",
+		AirlineIATA:       "ID",
+		Origin:            "CGK",
+		Destination:       "DPS",
+		DepartureDateTime: "2025-12-15T07:15:00+0700",
+		ArrivalDateTime:   "2025-12-15T10:00:00+0800",
+		Fare:              entity.BatikFare{BasePrice: 980000, Taxes: 120000, TotalPrice: 1100000, CurrencyCode: "IDR", Class: "Y"},
+		SeatsAvailable:    32,
+		BaggageInfo:       "7kg cabin, 20kg checked",
+	}
+}
+
+func TestMapFlightCabinClass(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"Y", entity.CABIN_ECONOMY},
+		{"q", entity.CABIN_ECONOMY},
+		{"W", entity.CABIN_PREMIUM_ECONOMY},
+		{"C", entity.CABIN_BUSINESS},
+		{"F", entity.CABIN_FIRST},
+		{"Economy", entity.CABIN_ECONOMY},
+		{"X", entity.CABIN_UNKNOWN},
+	}
+
+	b := newTestService()
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			raw := testFlight()
+			raw.Fare.Class = tt.raw
+
+			fl, err := b.mapFlight(raw)
+			if err != nil {
+				t.Fatalf("mapFlight: %v", err)
+			}
+			if fl.CabinClass != tt.want || fl.FareClass != tt.raw {
+				t.Errorf("cabin = %q, fare class %q, want %q and the raw %q", fl.CabinClass, fl.FareClass, tt.want, tt.raw)
+			}
+		})
+	}
+}
+
+func TestMapFlightLayovers(t *testing.T) {
+	tests := []struct {
+		name        string
+		connections []entity.BatikConnection
+		want        []entity.Layover
+	}{
+		{"direct", nil, []entity.Layover{}},
+		{
+			name:        "a duration in minutes",
+			connections: []entity.BatikConnection{{StopAirport: "SUB", StopDuration: "55m"}},
+			want:        []entity.Layover{{Airport: "SUB", AirportName: "Juanda International", City: "Surabaya", DurationMinutes: 55}},
+		},
+		{
+			name:        "hours and minutes, and an airport missing from the registry",
+			connections: []entity.BatikConnection{{StopAirport: "SUB", StopDuration: "1h 45m"}, {StopAirport: "UPG", StopDuration: "2h"}},
+			want: []entity.Layover{
+				{Airport: "SUB", AirportName: "Juanda International", City: "Surabaya", DurationMinutes: 105},
+				{Airport: "UPG", DurationMinutes: 120},
+			},
+		},
+		{
+			name:        "a duration that can not be read is 0",
+			connections: []entity.BatikConnection{{StopAirport: "SUB", StopDuration: "about an hour"}},
+			want:        []entity.Layover{{Airport: "SUB", AirportName: "Juanda International", City: "Surabaya"}},
+		},
+	}
+
+	b := newTestService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := testFlight()
+			raw.NumberOfStops, raw.Connections = len(tt.connections), tt.connections
+
+			fl, err := b.mapFlight(raw)
+			if err != nil {
+				t.Fatalf("mapFlight: %v", err)
+			}
+			if fl.Stops != len(tt.want) {
+				t.Errorf("stops = %d, want %d", fl.Stops, len(tt.want))
+			}
+			if !slices.Equal(fl.Layovers, tt.want) {
+				t.Errorf("layovers = %+v, want %+v", fl.Layovers, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapFlightLocations(t *testing.T) {
+	fl, err := newTestService().mapFlight(testFlight())
+	if err != nil {
+		t.Fatalf("mapFlight: %v", err)
+	}
+
+	if fl.Departure.Airport != "Soekarno-Hatta International" || fl.Departure.City != "Jakarta" || fl.Departure.Timezone != "Asia/Jakarta" {
+		t.Errorf("departure = %+v", fl.Departure)
+	}
+	if fl.Arrival.Airport != "I Gusti Ngurah Rai International" || fl.Arrival.City != "Denpasar" || fl.Arrival.Timezone != "Asia/Makassar" {
+		t.Errorf("arrival = %+v", fl.Arrival)
+	}
+	if dep, arr := fl.Departure.Datetime.Format("15:04 MST"), fl.Arrival.Datetime.Format("15:04 MST"); dep != "07:15 WIB" || arr != "10:00 WITA" {
+		t.Errorf("times = %s to %s, want 07:15 WIB to 10:00 WITA", dep, arr)
+	}
+	if fl.Duration.TotalMinutes != 105 || fl.Duration.Formatted != "1h 45m" {
+		t.Errorf("duration = %d (%s), want 105 (1h 45m)", fl.Duration.TotalMinutes, fl.Duration.Formatted)
+	}
+	if fl.Baggage.CarryOn != "7kg" || fl.Baggage.Checked != "20kg" {
+		t.Errorf("baggage = %+v, want 7kg and 20kg", fl.Baggage)
+	}
+}
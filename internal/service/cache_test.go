@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"flight-aggregator/internal/entity"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	ttl := DefaultConfig().Cache.Default
+
+	tests := []struct {
+		name string
+		// age of the cached entry, none when nil
+		age *time.Duration
+		// override is the TTL of the provider when set
+		override   *CacheTTL
+		wantStatus string
+		wantIDs    []string
+		// refreshed is whether the provider is called, in the background for a stale entry
+		refreshed bool
+	}{
+		{"nothing cached", nil, nil, entity.CACHE_MISS, []string{"LIVE"}, true},
+		{"fresh", ptr(time.Duration(0)), nil, entity.CACHE_FRESH, []string{"CACHED"}, false},
+		{"at the end of fresh", ptr(ttl.Fresh - time.Second), nil, entity.CACHE_FRESH, []string{"CACHED"}, false},
+		{"stale is served and refreshed", ptr(ttl.Fresh + time.Second), nil, entity.CACHE_STALE, []string{"CACHED"}, true},
+		{"past stale is a miss", ptr(ttl.Fresh + ttl.Stale + time.Second), nil, entity.CACHE_MISS, []string{"LIVE"}, true},
+		{"a provider TTL overrides the default", ptr(ttl.Fresh + time.Second), &CacheTTL{Fresh: time.Hour}, entity.CACHE_FRESH, []string{"CACHED"}, false},
+		{"a provider without a stale window", ptr(2 * time.Second), &CacheTTL{Fresh: time.Second}, entity.CACHE_MISS, []string{"LIVE"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			garuda := &fakeProvider{code: "Garuda", name: "Garuda Indonesia", flights: map[string][]entity.Flight{
+				routeKey("CGK", "DPS", "2025-12-15"): {testFlight("LIVE", "Garuda Indonesia", 1000000)},
+			}}
+			cache := newMemCache()
+			f := newTestService(t, cache, garuda)
+			if tt.override != nil {
+				f.config.Cache.Providers = map[string]CacheTTL{"Garuda": *tt.override}
+			}
+
+			req := entity.SearchRequest{Origin: "CGK", Destination: []string{"DPS"}, DepartureDate: "2025-12-15"}
+			f.standardizeRequest(&req)
+			key := f.cacheKey(req, "Garuda")
+			if tt.age != nil {
+				cache.Set(context.Background(), key, entity.CachedFlights{
+					Flights:  []entity.Flight{testFlight("CACHED", "Garuda Indonesia", 1000000)},
+					CachedAt: time.Now().Add(-*tt.age),
+				}, 0)
+			}
+
+			res, err := f.SearchFlight(context.Background(), req)
+			if err != nil {
+				t.Fatalf("SearchFlight: %v", err)
+			}
+			if got := res.Metadata.CacheStatus["Garuda"]; got != tt.wantStatus {
+				t.Errorf("cache status = %q, want %q", got, tt.wantStatus)
+			}
+			if got := flightIDs(res.Flights); !slices.Equal(got, tt.wantIDs) {
+				t.Errorf("flights = %v, want %v", got, tt.wantIDs)
+			}
+
+			if !tt.refreshed {
+				if calls := garuda.calls(); calls != 0 {
+					t.Errorf("provider calls = %d, want none", calls)
+				}
+				return
+			}
+			// a stale entry is refreshed after the answer, wait for the new one
+			deadline := time.Now().Add(time.Second)
+			for {
+				var entry entity.CachedFlights
+				if cache.Get(context.Background(), key, &entry) == nil && slices.Equal(flightIDs(entry.Flights), []string{"LIVE"}) {
+					if time.Since(entry.CachedAt) > time.Second {
+						t.Errorf("refreshed entry is %s old", time.Since(entry.CachedAt))
+					}
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatal("the cache was not refreshed")
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+			if calls := garuda.calls(); calls != 1 {
+				t.Errorf("provider calls = %d, want 1", calls)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
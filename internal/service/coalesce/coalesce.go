@@ -0,0 +1,159 @@
+package coalesce
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type call[T any] struct {
+	done    chan struct{}
+	value   T
+	err     error
+	waiters int
+	ctx     *callContext
+}
+
+// Group runs at most one fn per key at a time. Callers asking for a key that is
+// already in flight wait for that call and share its result.
+type Group[T any] struct {
+	// Timeout bounds every call however long its callers would wait, 0 leaves it to them
+	Timeout time.Duration
+
+	mu    sync.Mutex
+	calls map[string]*call[T]
+}
+
+// Do runs fn for key unless an identical call is in flight, in which case it waits
+// for that one. fn gets a context of its own: its deadline is the latest one of the
+// callers still waiting, and it is cancelled once every caller has given up, so one
+// caller leaving does not cancel the work the others are waiting for. shared reports
+// whether the result was also handed to another caller.
+func (g *Group[T]) Do(ctx context.Context, key string, fn func(ctx context.Context) (T, error)) (value T, shared bool, err error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*call[T])
+	}
+
+	c, inFlight := g.calls[key]
+	if !inFlight {
+		c = &call[T]{done: make(chan struct{}), ctx: newCallContext(ctx, g.Timeout)}
+		g.calls[key] = c
+	}
+	c.waiters++
+	waiter := c.ctx.join(ctx)
+	g.mu.Unlock()
+
+	if !inFlight {
+		go g.run(key, c, fn)
+	}
+
+	select {
+	case <-c.done:
+		g.mu.Lock()
+		shared = c.waiters > 1
+		g.mu.Unlock()
+		return c.value, shared, c.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		if c.ctx.leave(waiter) && g.calls[key] == c {
+			// nobody waits for it any more, the next caller starts a new call
+			delete(g.calls, key)
+		}
+		g.mu.Unlock()
+		var zero T
+		return zero, false, ctx.Err()
+	}
+}
+
+func (g *Group[T]) run(key string, c *call[T], fn func(ctx context.Context) (T, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.err = fmt.Errorf("coalesce: panic in %s: %v", key, r)
+		}
+
+		g.mu.Lock()
+		if g.calls[key] == c {
+			delete(g.calls, key)
+		}
+		g.mu.Unlock()
+		c.ctx.cancel()
+		close(c.done)
+	}()
+
+	c.value, c.err = fn(c.ctx)
+}
+
+// callContext is the context of a shared call. It carries the values of the caller
+// that started it and reports the latest deadline of the callers still waiting.
+type callContext struct {
+	context.Context
+	cancel context.CancelFunc
+	// limit is when Group.Timeout passes, zero without one
+	limit time.Time
+
+	mu   sync.Mutex
+	next int
+	// waiting holds the deadline of every caller still waiting, zero for none
+	waiting map[int]time.Time
+}
+
+func newCallContext(ctx context.Context, timeout time.Duration) *callContext {
+	c := &callContext{waiting: make(map[int]time.Time)}
+	if timeout > 0 {
+		c.limit = time.Now().Add(timeout)
+		c.Context, c.cancel = context.WithDeadline(context.WithoutCancel(ctx), c.limit)
+	} else {
+		c.Context, c.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	}
+	return c
+}
+
+// join adds a waiting caller and returns its id for leave
+func (c *callContext) join(ctx context.Context) int {
+	deadline, _ := ctx.Deadline()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id := c.next
+	c.next++
+	c.waiting[id] = deadline
+	return id
+}
+
+// leave removes a caller that gave up, the call is cancelled when it was the last one
+func (c *callContext) leave(id int) (last bool) {
+	c.mu.Lock()
+	delete(c.waiting, id)
+	last = len(c.waiting) == 0
+	c.mu.Unlock()
+
+	if last {
+		c.cancel()
+	}
+	return last
+}
+
+// Deadline is the latest deadline of the waiting callers, capped by Group.Timeout.
+// A caller without a deadline leaves only the cap.
+func (c *callContext) Deadline() (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var latest time.Time
+	for _, deadline := range c.waiting {
+		if deadline.IsZero() {
+			latest = time.Time{}
+			break
+		}
+		if deadline.After(latest) {
+			latest = deadline
+		}
+	}
+
+	if latest.IsZero() || (!c.limit.IsZero() && c.limit.Before(latest)) {
+		return c.limit, !c.limit.IsZero()
+	}
+	return latest, true
+}
@@ -0,0 +1,274 @@
+package coalesce
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDoSharesOneCall(t *testing.T) {
+	var g Group[string]
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	const callers = 5
+	type result struct {
+		value  string
+		shared bool
+		err    error
+	}
+	results := make(chan result, callers)
+
+	for i := 0; i < callers; i++ {
+		go func() {
+			value, shared, err := g.Do(context.Background(), "CGK-DPS", func(context.Context) (string, error) {
+				calls.Add(1)
+				<-release
+				return "flights", nil
+			})
+			results <- result{value, shared, err}
+		}()
+	}
+	// let every caller join the call in flight before it answers
+	waitForWaiters(t, &g, "CGK-DPS", callers)
+	close(release)
+
+	for i := 0; i < callers; i++ {
+		res := <-results
+		if res.err != nil || res.value != "flights" {
+			t.Errorf("Do() = %q, %v, want the shared result", res.value, res.err)
+		}
+		if !res.shared {
+			t.Error("shared = false, want true")
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("fn ran %d times, want 1", n)
+	}
+}
+
+func TestDoRunsAgainOnceTheCallIsDone(t *testing.T) {
+	var g Group[int]
+	calls := 0
+	fn := func(context.Context) (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	first, shared, _ := g.Do(context.Background(), "key", fn)
+	if shared {
+		t.Error("a lone call reported shared")
+	}
+	second, _, _ := g.Do(context.Background(), "key", fn)
+	if first != 1 || second != 2 {
+		t.Errorf("Do() = %d then %d, want a new call each time", first, second)
+	}
+}
+
+func TestDoKeepsKeysApart(t *testing.T) {
+	var g Group[string]
+
+	a, _, _ := g.Do(context.Background(), "a", func(context.Context) (string, error) { return "a", nil })
+	b, _, _ := g.Do(context.Background(), "b", func(context.Context) (string, error) { return "b", nil })
+	if a != "a" || b != "b" {
+		t.Errorf("Do() = %q, %q, want a result per key", a, b)
+	}
+}
+
+func TestDoCallerCancellationLeavesOthersWaiting(t *testing.T) {
+	var g Group[string]
+	release := make(chan struct{})
+	fn := func(context.Context) (string, error) {
+		<-release
+		return "flights", nil
+	}
+
+	leaving, leave := context.WithCancel(context.Background())
+	leftErr := make(chan error, 1)
+	go func() {
+		_, _, err := g.Do(leaving, "CGK-DPS", fn)
+		leftErr <- err
+	}()
+
+	stayed := make(chan string, 1)
+	go func() {
+		value, _, err := g.Do(context.Background(), "CGK-DPS", fn)
+		if err != nil {
+			value = err.Error()
+		}
+		stayed <- value
+	}()
+	waitForWaiters(t, &g, "CGK-DPS", 2)
+
+	leave()
+	if err := <-leftErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled caller got %v, want context.Canceled", err)
+	}
+
+	// the call keeps running for the caller that stayed
+	close(release)
+	select {
+	case value := <-stayed:
+		if value != "flights" {
+			t.Errorf("remaining caller got %q, want flights", value)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("remaining caller never got the result")
+	}
+}
+
+func TestDoRecoversFromPanic(t *testing.T) {
+	var g Group[string]
+
+	_, _, err := g.Do(context.Background(), "key", func(context.Context) (string, error) {
+		panic("provider blew up")
+	})
+	if err == nil {
+		t.Fatal("Do() err = nil, want the panic as an error")
+	}
+
+	// the key is free again
+	value, _, err := g.Do(context.Background(), "key", func(context.Context) (string, error) { return "ok", nil })
+	if err != nil || value != "ok" {
+		t.Errorf("Do() after a panic = %q, %v, want ok", value, err)
+	}
+}
+
+func TestDoDeadlineIsTheLatestWaiter(t *testing.T) {
+	var g Group[string]
+	deadlines := make(chan time.Time)
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (string, error) {
+		for range release {
+			deadline, _ := ctx.Deadline()
+			deadlines <- deadline
+		}
+		return "flights", nil
+	}
+
+	now := time.Now()
+	early, cancelEarly := context.WithDeadline(context.Background(), now.Add(time.Minute))
+	defer cancelEarly()
+	late, cancelLate := context.WithDeadline(context.Background(), now.Add(time.Hour))
+
+	go g.Do(early, "CGK-DPS", fn)
+	lateErr := make(chan error, 1)
+	go func() {
+		_, _, err := g.Do(late, "CGK-DPS", fn)
+		lateErr <- err
+	}()
+	waitForWaiters(t, &g, "CGK-DPS", 2)
+
+	release <- struct{}{}
+	if got := <-deadlines; !got.Equal(now.Add(time.Hour)) {
+		t.Errorf("deadline with both callers = %v, want the later one %v", got, now.Add(time.Hour))
+	}
+
+	// the caller with the later deadline gives up, the call keeps the other one's
+	cancelLate()
+	<-lateErr
+	release <- struct{}{}
+	if got := <-deadlines; !got.Equal(now.Add(time.Minute)) {
+		t.Errorf("deadline after the later caller left = %v, want %v", got, now.Add(time.Minute))
+	}
+	close(release)
+}
+
+func TestDoCancelsOnceEveryCallerLeft(t *testing.T) {
+	var g Group[string]
+	cancelled := make(chan error, 1)
+	fn := func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+		return "", ctx.Err()
+	}
+
+	first, cancelFirst := context.WithCancel(context.Background())
+	second, cancelSecond := context.WithCancel(context.Background())
+	done := make(chan struct{}, 2)
+	for _, ctx := range []context.Context{first, second} {
+		go func() {
+			g.Do(ctx, "CGK-DPS", fn)
+			done <- struct{}{}
+		}()
+	}
+	waitForWaiters(t, &g, "CGK-DPS", 2)
+
+	cancelFirst()
+	<-done
+	select {
+	case <-cancelled:
+		t.Fatal("the call was cancelled while a caller still waited")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancelSecond()
+	<-done
+	select {
+	case err := <-cancelled:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("call context err = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the call was not cancelled after every caller left")
+	}
+
+	// the abandoned call no longer takes new callers
+	value, _, err := g.Do(context.Background(), "CGK-DPS", func(context.Context) (string, error) { return "fresh", nil })
+	if err != nil || value != "fresh" {
+		t.Errorf("Do() after the call was abandoned = %q, %v, want a new call", value, err)
+	}
+}
+
+func TestDoTimeoutCapsTheCall(t *testing.T) {
+	g := Group[time.Time]{Timeout: 50 * time.Millisecond}
+
+	tests := []struct {
+		name   string
+		caller time.Duration
+		want   time.Duration
+	}{
+		{"caller without a deadline", 0, 50 * time.Millisecond},
+		{"caller with a later deadline", time.Hour, 50 * time.Millisecond},
+		{"caller with an earlier deadline", 20 * time.Millisecond, 20 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.caller > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.caller)
+				defer cancel()
+			}
+
+			start := time.Now()
+			deadline, _, _ := g.Do(ctx, tt.name, func(ctx context.Context) (time.Time, error) {
+				deadline, _ := ctx.Deadline()
+				return deadline, nil
+			})
+			if got := deadline.Sub(start).Round(10 * time.Millisecond); got != tt.want {
+				t.Errorf("call deadline = %s after the start, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// waitForWaiters blocks until n callers wait on key
+func waitForWaiters[T any](t *testing.T, g *Group[T], key string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		g.mu.Lock()
+		c, ok := g.calls[key]
+		joined := ok && c.waiters >= n
+		g.mu.Unlock()
+		if joined {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("%d callers never joined %q", n, key)
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"flight-aggregator/internal/service/resilience"
+	"time"
+)
+
+// Config tunes how the flight service talks to its providers
+type Config struct {
+	// Breaker applies to every provider, each provider gets its own breaker
+	Breaker resilience.BreakerConfig
+	Retry   resilience.RetryConfig
+	Hedge   resilience.HedgeConfig
+	// ProviderBudget bounds all attempts (retries and hedges) of one provider in one search
+	ProviderBudget time.Duration
+	// SearchDeadline is how long a search waits for providers before answering with
+	// what it has, 0 waits for every provider. A request can override it with maxWaitMs.
+	SearchDeadline time.Duration
+	Cache          CacheConfig
+	Fares          FareConfig
+	// DisplayCurrency is used when a search does not ask for one
+	DisplayCurrency string
+	BestValue       BestValueWeights
+}
+
+// BestValueWeights turn a flight into a score in rupiah, lower is better:
+// party price + minutes * TimePerMinute + stops * StopPenalty - amenities * AmenityBonus
+type BestValueWeights struct {
+	TimePerMinute float64
+	StopPenalty   float64
+	AmenityBonus  float64
+}
+
+// CacheTTL splits the life of a cached provider response in two windows.
+// Within Fresh it is served as is. Within the following Stale window it is
+// served immediately and refreshed in the background. After that it is a miss.
+type CacheTTL struct {
+	Fresh time.Duration
+	Stale time.Duration
+}
+
+type CacheConfig struct {
+	Default CacheTTL
+	// Providers overrides Default per provider code
+	Providers map[string]CacheTTL
+}
+
+func (c CacheConfig) TTLFor(code string) CacheTTL {
+	if ttl, ok := c.Providers[code]; ok {
+		return ttl
+	}
+	return c.Default
+}
+
+// FareRule prices children and infants as a fraction of the adult fare
+type FareRule struct {
+	Child  float64
+	Infant float64
+}
+
+type FareConfig struct {
+	Default FareRule
+	// Providers overrides Default per provider code
+	Providers map[string]FareRule
+}
+
+func (c FareConfig) RuleFor(code string) FareRule {
+	if rule, ok := c.Providers[code]; ok {
+		return rule
+	}
+	return c.Default
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Breaker:         resilience.DefaultBreakerConfig(),
+		Retry:           resilience.DefaultRetryConfig(),
+		Hedge:           resilience.DefaultHedgeConfig(),
+		ProviderBudget:  3 * time.Second,
+		SearchDeadline:  2500 * time.Millisecond,
+		DisplayCurrency: "IDR",
+		BestValue: BestValueWeights{
+			TimePerMinute: 2500,
+			StopPenalty:   150000,
+			AmenityBonus:  50000,
+		},
+		Cache: CacheConfig{
+			Default: CacheTTL{Fresh: 1 * time.Minute, Stale: 5 * time.Minute},
+		},
+		Fares: FareConfig{
+			Default: FareRule{Child: 0.75, Infant: 0.10},
+			Providers: map[string]FareRule{
+				// low cost carriers sell children a full seat
+				"LionAir": {Child: 1, Infant: 0.10},
+				"AirAsia": {Child: 1, Infant: 0.10},
+			},
+		},
+	}
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"flight-aggregator/internal/common/util"
+	"flight-aggregator/internal/entity"
+	"flight-aggregator/internal/redis"
+	"flight-aggregator/internal/service/fx"
+	"flight-aggregator/internal/service/provider"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeProvider answers every query with the flights of its route and date
+type fakeProvider struct {
+	code, name string
+	// flights are keyed by "ORIGIN-DEST_DATE", a missing key has no flights
+	flights map[string][]entity.Flight
+	err     error
+
+	mu      sync.Mutex
+	queries []provider.Query
+}
+
+func (p *fakeProvider) Code() string { return p.code }
+func (p *fakeProvider) Name() string { return p.name }
+
+func (p *fakeProvider) GetFlight(ctx context.Context, query provider.Query) ([]entity.Flight, error) {
+	p.mu.Lock()
+	p.queries = append(p.queries, query)
+	p.mu.Unlock()
+
+	if p.err != nil {
+		return nil, p.err
+	}
+	var flights []entity.Flight
+	for _, dest := range query.Destinations {
+		flights = append(flights, p.flights[routeKey(query.Origin, dest, query.DepartureDate)]...)
+	}
+	return flights, nil
+}
+
+// calls is how many times the provider was asked
+func (p *fakeProvider) calls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.queries)
+}
+
+func routeKey(origin, dest, date string) string {
+	return fmt.Sprintf("%s-%s_%s", origin, dest, date)
+}
+
+// memCache keeps entries as JSON, like redis, and ignores the ttl
+type memCache struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+}
+
+func newMemCache() *memCache {
+	return &memCache{entries: make(map[string][]byte)}
+}
+
+func (c *memCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = data
+	return nil
+}
+
+func (c *memCache) Get(ctx context.Context, key string, target interface{}) error {
+	c.mu.Lock()
+	data, ok := c.entries[key]
+	c.mu.Unlock()
+	if !ok {
+		return redis.ErrKeyNotFound
+	}
+	return json.Unmarshal(data, target)
+}
+
+func (c *memCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+	return nil
+}
+
+// testRates values one unit of each currency in rupiah
+func testRates(t *testing.T) fx.RateSource {
+	t.Helper()
+
+	rates, err := fx.NewTable("IDR", map[string]float64{"USD": 16000, "SGD": 12000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rates
+}
+
+// newTestService builds a service over providers with a cache that starts empty
+func newTestService(t *testing.T, cache redis.RedisService, providers ...provider.Provider) *flightService {
+	t.Helper()
+
+	registry, err := provider.NewRegistry(providers...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := DefaultConfig()
+	config.SearchDeadline = 0
+	return NewFlightService(registry, cache, testRates(t), config, slog.New(slog.DiscardHandler)).(*flightService)
+}
+
+// testFlight is a direct CGK to DPS flight of provider name at price rupiah a seat
+func testFlight(id, name string, price float64) entity.Flight {
+	return legFlight(id, name, "CGK", "DPS", time.Date(2025, 12, 15, 6, 0, 0, 0, time.UTC), 120, price)
+}
+
+// legFlight is a direct flight from origin to dest leaving at dep
+func legFlight(id, name, origin, dest string, dep time.Time, minutes int, price float64) entity.Flight {
+	arr := dep.Add(time.Duration(minutes) * time.Minute)
+	return entity.Flight{
+		ID:             id,
+		Provider:       name,
+		FlightNumber:   id,
+		Departure:      entity.LocationDetails{Code: origin, Datetime: dep, Timestamp: dep.Unix()},
+		Arrival:        entity.LocationDetails{Code: dest, Datetime: arr, Timestamp: arr.Unix()},
+		Duration:       entity.DurationDetails{TotalMinutes: minutes, Formatted: fmt.Sprintf("%dh %dm", minutes/60, minutes%60)},
+		Price:          entity.PriceDetails{Amount: price, Currency: "IDR", Formatted: util.FormatMoney(price, "IDR")},
+		AvailableSeats: 9,
+		CabinClass:     entity.CABIN_ECONOMY,
+	}
+}
+
+// flightIDs lists the IDs of flights in order
+func flightIDs(flights []entity.Flight) []string {
+	ids := make([]string, 0, len(flights))
+	for _, fl := range flights {
+		ids = append(ids, fl.ID)
+	}
+	return ids
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"flight-aggregator/internal/entity"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SearchFareCalendar runs one cached one-way search per day of the window, so days
+// already in Redis cost nothing and only the missing ones hit the providers
+func (f *flightService) SearchFareCalendar(ctx context.Context, req entity.FareCalendarRequest) (entity.FareCalendarResponse, error) {
+	startTime := time.Now()
+
+	if err := req.Validate(); err != nil {
+		return entity.FareCalendarResponse{}, fmt.Errorf("%w: %w", entity.ErrInvalidRequest, err)
+	}
+	if err := f.checkDisplayCurrency(req.DisplayCurrency); err != nil {
+		return entity.FareCalendarResponse{}, err
+	}
+	f.standardizeRequest(&req.SearchRequest)
+
+	ctx, cancel := f.withSearchDeadline(ctx, req.MaxWaitMs)
+	defer cancel()
+
+	dates := req.Dates()
+	results := make([]legResult, len(dates))
+	var wg sync.WaitGroup
+	for i, date := range dates {
+		dayReq := req.SearchRequest
+		dayReq.DepartureDate = date
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = f.searchLeg(ctx, dayReq)
+		}(i)
+	}
+	wg.Wait()
+
+	response := entity.FareCalendarResponse{
+		SearchCriteria: newSearchCriteria(req.SearchRequest),
+		Days:           make([]entity.FareCalendarDay, len(dates)),
+	}
+
+	for i, res := range results {
+		response.Days[i] = entity.FareCalendarDay{
+			Date:         dates[i],
+			Cheapest:     cheapestFlight(res.flights),
+			BestValue:    res.bestValue,
+			TotalResults: len(res.flights),
+			CacheStatus:  res.cacheStatus,
+		}
+	}
+	response.Metadata = f.summarizeLegs(results...)
+	response.Metadata.SearchTimeMs = time.Since(startTime).Milliseconds()
+
+	return response, nil
+}
+
+func cheapestFlight(flights []entity.Flight) *entity.Flight {
+	var cheapest *entity.Flight
+	for i := range flights {
+		if cheapest == nil || flights[i].Price.TotalAmount < cheapest.Price.TotalAmount {
+			cheapest = &flights[i]
+		}
+	}
+	return cheapest
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"flight-aggregator/internal/entity"
+	"slices"
+	"testing"
+	"time"
+)
+
+// calendarProvider flies CGK to DPS every day from the 12th to the 18th, a day's fare
+// is 100,000 rupiah a seat per day of the month
+func calendarProvider() *fakeProvider {
+	p := &fakeProvider{code: "Garuda", name: "Garuda Indonesia", flights: make(map[string][]entity.Flight)}
+	for day := 12; day <= 18; day++ {
+		dep := time.Date(2025, 12, day, 6, 0, 0, 0, time.UTC)
+		fl := legFlight("GA"+dep.Format("02"), p.name, "CGK", "DPS", dep, 120, float64(day)*100000)
+		p.flights[routeKey("CGK", "DPS", dep.Format(entity.DateLayout))] = []entity.Flight{fl}
+	}
+	return p
+}
+
+func calendarRequest(window *int) entity.FareCalendarRequest {
+	return entity.FareCalendarRequest{
+		SearchRequest: entity.SearchRequest{
+			Origin:        "CGK",
+			Destination:   []string{"DPS"},
+			DepartureDate: "2025-12-15",
+		},
+		WindowDays: window,
+	}
+}
+
+func TestFareCalendarWindow(t *testing.T) {
+	zero, two, outside := 0, 2, 5
+
+	tests := []struct {
+		name   string
+		window *int
+		want   []string
+		// cheapest is the flight of each day, "" for a day without flights
+		cheapest []string
+	}{
+		{"default window", nil,
+			[]string{"2025-12-12", "2025-12-13", "2025-12-14", "2025-12-15", "2025-12-16", "2025-12-17", "2025-12-18"},
+			[]string{"GA12", "GA13", "GA14", "GA15", "GA16", "GA17", "GA18"}},
+		{"departure date alone", &zero, []string{"2025-12-15"}, []string{"GA15"}},
+		{"two days each side", &two,
+			[]string{"2025-12-13", "2025-12-14", "2025-12-15", "2025-12-16", "2025-12-17"},
+			[]string{"GA13", "GA14", "GA15", "GA16", "GA17"}},
+		{"days without flights are empty", &outside,
+			[]string{"2025-12-10", "2025-12-11", "2025-12-12", "2025-12-13", "2025-12-14", "2025-12-15", "2025-12-16", "2025-12-17", "2025-12-18", "2025-12-19", "2025-12-20"},
+			[]string{"", "", "GA12", "GA13", "GA14", "GA15", "GA16", "GA17", "GA18", "", ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			garuda := calendarProvider()
+			f := newTestService(t, newMemCache(), garuda)
+
+			res, err := f.SearchFareCalendar(context.Background(), calendarRequest(tt.window))
+			if err != nil {
+				t.Fatalf("SearchFareCalendar: %v", err)
+			}
+
+			var dates, cheapest []string
+			for _, day := range res.Days {
+				dates = append(dates, day.Date)
+				id := ""
+				if day.Cheapest != nil {
+					id = day.Cheapest.ID
+				}
+				cheapest = append(cheapest, id)
+			}
+			if !slices.Equal(dates, tt.want) {
+				t.Errorf("dates = %v, want %v", dates, tt.want)
+			}
+			if !slices.Equal(cheapest, tt.cheapest) {
+				t.Errorf("cheapest = %v, want %v", cheapest, tt.cheapest)
+			}
+			if got := garuda.calls(); got != len(tt.want) {
+				t.Errorf("provider calls = %d, want one per day, %d", got, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestFareCalendarReusesCachedDays(t *testing.T) {
+	one, two := 1, 2
+
+	tests := []struct {
+		name string
+		// then is searched after a calendar of one adult in economy, one day each side
+		then func(req *entity.FareCalendarRequest)
+		// calls is how many more days the provider is asked for
+		calls int
+	}{
+		{"the same search", func(req *entity.FareCalendarRequest) {}, 0},
+		{"another passenger count", func(req *entity.FareCalendarRequest) { req.Passanger = 4 }, 0},
+		{"a family", func(req *entity.FareCalendarRequest) { req.Adults, req.Children, req.Infants = 2, 1, 1 }, 0},
+		{"another cabin", func(req *entity.FareCalendarRequest) { req.CabinClass = "business" }, 0},
+		{"a wider window only fetches the new days", func(req *entity.FareCalendarRequest) { req.WindowDays = &two }, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			garuda := calendarProvider()
+			f := newTestService(t, newMemCache(), garuda)
+
+			first := calendarRequest(&one)
+			first.Passanger, first.CabinClass = 1, "economy"
+			if _, err := f.SearchFareCalendar(context.Background(), first); err != nil {
+				t.Fatalf("first SearchFareCalendar: %v", err)
+			}
+			fetched := garuda.calls()
+
+			req := calendarRequest(&one)
+			tt.then(&req)
+			res, err := f.SearchFareCalendar(context.Background(), req)
+			if err != nil {
+				t.Fatalf("SearchFareCalendar: %v", err)
+			}
+
+			if got := garuda.calls() - fetched; got != tt.calls {
+				t.Errorf("provider calls = %d, want %d", got, tt.calls)
+			}
+			fresh := 0
+			for _, day := range res.Days {
+				if day.CacheStatus["Garuda"] == entity.CACHE_FRESH {
+					fresh++
+				}
+			}
+			if want := 3; fresh != want {
+				t.Errorf("days served from the cache = %d, want %d", fresh, want)
+			}
+		})
+	}
+}
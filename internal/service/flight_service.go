@@ -2,101 +2,299 @@ package service
 
 import (
 	"context"
-	logger "flight-aggregator/internal/common"
+	"errors"
 	"flight-aggregator/internal/entity"
 	"flight-aggregator/internal/redis"
-	"flight-aggregator/internal/service/airasia"
-	"flight-aggregator/internal/service/batikair"
-	"flight-aggregator/internal/service/garuda"
-	"flight-aggregator/internal/service/lionair"
+	"flight-aggregator/internal/service/coalesce"
+	"flight-aggregator/internal/service/fx"
+	"flight-aggregator/internal/service/provider"
+	"flight-aggregator/internal/service/resilience"
 	"fmt"
+	"log/slog"
 	"math"
 	"sort"
 	"strings"
 	"sync"
-	"sync/atomic"
 	"time"
 )
 
 type flightService struct {
-	garudaService   garuda.GarudaService
-	batikAirService batikair.BatikAirService
-	lionAirService  lionair.LionAirService
-	airAsiaService  airasia.AirAsiaService
-	redisService    redis.RedisService
+	providers    provider.Registry
+	redisService redis.RedisService
+	rates        fx.RateSource
+	config       Config
+	logger       *slog.Logger
+
+	healthMu sync.Mutex
+	health   map[string]*providerHealth
+
+	// inflight shares one provider fetch between concurrent searches of the same cache key
+	inflight coalesce.Group[providerFetch]
+}
+
+// providerFetch is the outcome of one provider call, shared by every search waiting on it
+type providerFetch struct {
+	flights []entity.Flight
+	retries int
+	// skipped is set when the breaker was open and the provider was not called
+	skipped bool
+}
+
+// providerHealth is what the service remembers about a provider between searches
+type providerHealth struct {
+	breaker resilience.CircuitBreaker
+	latency resilience.LatencyTracker
 }
 
 type FlightService interface {
 	SearchFlight(ctx context.Context, req entity.SearchRequest) (entity.SearchResponse, error)
+	SearchFlightStream(ctx context.Context, req entity.SearchRequest) (<-chan entity.SearchEvent, error)
+	SearchMultiCity(ctx context.Context, req entity.MultiCitySearchRequest) (entity.MultiCitySearchResponse, error)
+	SearchFareCalendar(ctx context.Context, req entity.FareCalendarRequest) (entity.FareCalendarResponse, error)
 }
 
-func NewFlightService(
-	garudaService garuda.GarudaService,
-	batikAirService batikair.BatikAirService,
-	lionAirService lionair.LionAirService,
-	airAsiaService airasia.AirAsiaService,
-	redisService redis.RedisService,
-) FlightService {
+func NewFlightService(providers provider.Registry, redisService redis.RedisService, rates fx.RateSource, config Config, logger *slog.Logger) FlightService {
 	return &flightService{
-		garudaService:   garudaService,
-		batikAirService: batikAirService,
-		lionAirService:  lionAirService,
-		airAsiaService:  airAsiaService,
-		redisService:    redisService,
+		providers:    providers,
+		redisService: redisService,
+		rates:        rates,
+		config:       config,
+		logger:       logger,
+		health:       make(map[string]*providerHealth),
+		inflight:     coalesce.Group[providerFetch]{Timeout: config.ProviderBudget},
 	}
 }
 
+// legResult is the outcome of searching one origin to its destinations on one date
+type legResult struct {
+	flights   []entity.Flight
+	bestValue *entity.Flight
+	// statuses is the outcome of every provider of the leg, one of the PROVIDER_ statuses
+	statuses map[string]string
+	// skipped providers were not called because their breaker is open
+	skipped []string
+	// timedOut providers had not answered by the search deadline
+	timedOut    []string
+	retries     map[string]int
+	cacheStatus map[string]string
+}
+
+// fetchResult is the outcome of calling the live providers of one leg
+type fetchResult struct {
+	flights  []entity.Flight
+	statuses map[string]string
+	skipped  []string
+	timedOut []string
+	retries  map[string]int
+}
+
 func (f *flightService) SearchFlight(ctx context.Context, req entity.SearchRequest) (entity.SearchResponse, error) {
 	startTime := time.Now()
 
 	if err := req.Validate(); err != nil {
+		return entity.SearchResponse{}, fmt.Errorf("%w: %w", entity.ErrInvalidRequest, err)
+	}
+	if err := f.checkDisplayCurrency(req.DisplayCurrency); err != nil {
 		return entity.SearchResponse{}, err
 	}
 	f.standardizeRequest(&req)
 
+	ctx, cancel := f.withSearchDeadline(ctx, req.MaxWaitMs)
+	defer cancel()
+
+	response := entity.SearchResponse{
+		SearchCriteria: newSearchCriteria(req),
+	}
+
+	var outbound, inbound legResult
+	if req.IsRoundTrip() {
+		// both legs are independent, fetch them in parallel
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			outbound = f.searchLeg(ctx, req)
+		}()
+		go func() {
+			defer wg.Done()
+			inbound = f.searchLeg(ctx, req.ReturnLeg())
+		}()
+		wg.Wait()
+
+		response.ReturnFlights = inbound.flights
+		response.CheapestRoundTrip, response.BestValueRoundTrip = f.pickRoundTrips(outbound.flights, inbound.flights)
+		response.Metadata = f.summarizeLegs(outbound, inbound)
+	} else {
+		outbound = f.searchLeg(ctx, req)
+		response.Metadata = f.summarizeLegs(outbound)
+	}
+
+	response.Flights = outbound.flights
+	response.BestValue = outbound.bestValue
+	response.Metadata.SearchTimeMs = time.Since(startTime).Milliseconds()
+
+	return response, nil
+}
+
+// searchLeg serves one leg from cache and live providers, then filters and sorts it
+func (f *flightService) searchLeg(ctx context.Context, req entity.SearchRequest) legResult {
 	// get from redis if not mark the airlines
-	cachedFlights, missingAirlines, succeeded := f.getCachedAirlines(ctx, req)
+	cachedFlights, missingAirlines, cacheStatus := f.getCachedAirlines(ctx, req)
 
 	// fetch mock airlines
-	var liveFlights []entity.Flight
-	var liveSucceeded int
-	var failed int
-	cacheHit := true
-
+	var live fetchResult
 	if len(missingAirlines) != 0 {
-		liveFlights, liveSucceeded, failed = f.fetchSpecificAirlines(ctx, req, missingAirlines)
-		cacheHit = false
+		live = f.fetchSpecificAirlines(ctx, req, missingAirlines)
+	}
+	allFlights := append(cachedFlights, live.flights...)
+	filteredFlights, bestValue := f.prepareFlights(ctx, allFlights, req)
+
+	// served from the cache counts as answered
+	statuses := make(map[string]string, len(cacheStatus))
+	for code, status := range cacheStatus {
+		if status != entity.CACHE_MISS {
+			statuses[code] = entity.PROVIDER_OK
+		}
+	}
+	for code, status := range live.statuses {
+		statuses[code] = status
 	}
-	allFlights := append(cachedFlights, liveFlights...)
+
+	return legResult{
+		flights:     filteredFlights,
+		bestValue:   bestValue,
+		statuses:    statuses,
+		skipped:     live.skipped,
+		timedOut:    live.timedOut,
+		retries:     live.retries,
+		cacheStatus: cacheStatus,
+	}
+}
+
+// callerDeadlineHeadroom is kept before the caller's deadline to send the answer
+const callerDeadlineHeadroom = 50 * time.Millisecond
+
+// prepareFlights converts, prices, filters and sorts provider flights for the request.
+// Prices are converted in place, so flights must not be shared with another search.
+func (f *flightService) prepareFlights(ctx context.Context, flights []entity.Flight, req entity.SearchRequest) ([]entity.Flight, *entity.Flight) {
+	// one currency before anything compares prices
+	flights = f.convertPrices(ctx, flights, req.DisplayCurrency)
+
+	// party totals first, sorting and the best value score use them
+	f.applyPassengerPricing(flights, req)
 
 	// Fillter
-	filteredFlights, bestValue := f.applyFiltersAndIdentifyBest(allFlights, req)
+	filteredFlights, bestValue := f.applyFiltersAndIdentifyBest(flights, req)
 
 	// Sort
 	if len(filteredFlights) > 0 {
 		f.applySorting(filteredFlights, req)
 	}
 
-	return entity.SearchResponse{
-		Flights: filteredFlights,
-		SearchCriteria: entity.SearchCriteria{
-			Origin:        req.Origin,
-			Destination:   req.Destination,
-			DepartureDate: req.DepartureDate,
-			Passengers:    req.Passanger,
-			CabinClass:    req.CabinClass,
-		},
-		Metadata: entity.Metadata{
-			TotalResults:       len(filteredFlights),
-			ProvidersQueried:   liveSucceeded + succeeded + failed,
-			ProvidersSucceeded: succeeded + liveSucceeded,
-			ProvidersFailed:    failed,
-			SearchTimeMs:       time.Since(startTime).Milliseconds(),
-			CacheHit:           cacheHit,
-		},
-		BestValue: bestValue,
-	}, nil
+	return filteredFlights, bestValue
+}
 
+// withSearchDeadline bounds how long a search waits for providers, maxWaitMs from the
+// request wins over the configured deadline. The deadline reaches the provider calls,
+// so the ones still running when it passes are cancelled.
+func (f *flightService) withSearchDeadline(ctx context.Context, maxWaitMs int) (context.Context, context.CancelFunc) {
+	deadline := f.config.SearchDeadline
+	if maxWaitMs > 0 {
+		deadline = time.Duration(maxWaitMs) * time.Millisecond
+	}
+	// a caller with its own deadline (a gRPC client) still gets the partial answer in time
+	if callerDeadline, ok := ctx.Deadline(); ok {
+		remaining := time.Until(callerDeadline) - callerDeadlineHeadroom
+		if deadline <= 0 || remaining < deadline {
+			deadline = max(remaining, time.Millisecond)
+		}
+	}
+	if deadline <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, deadline)
+}
+
+// cacheHealthy is false while the cache backend is unreachable and searches run on memory and live fetches
+func (f *flightService) cacheHealthy() bool {
+	if checker, ok := f.redisService.(redis.HealthChecker); ok {
+		return checker.Healthy()
+	}
+	return true
+}
+
+var cacheStatusRank = map[string]int{
+	entity.CACHE_FRESH: 1,
+	entity.CACHE_STALE: 2,
+	entity.CACHE_MISS:  3,
+}
+
+// providerStatusRank orders the outcomes of a provider that was asked, the worst one wins
+var providerStatusRank = map[string]int{
+	entity.PROVIDER_OK:        1,
+	entity.PROVIDER_TIMED_OUT: 2,
+	entity.PROVIDER_FAILED:    3,
+}
+
+// summarizeLegs merges the provider counters of every leg of one search. A provider
+// is counted once however many legs or days it served, with its worst outcome.
+func (f *flightService) summarizeLegs(legs ...legResult) entity.Metadata {
+	meta := entity.Metadata{
+		CacheStatus:  make(map[string]string),
+		CacheHealthy: f.cacheHealthy(),
+	}
+	outcomes := make(map[string]string)
+	skipped := make(map[string]bool)
+	timedOut := make(map[string]bool)
+
+	for _, leg := range legs {
+		meta.TotalResults += len(leg.flights)
+		for code, status := range leg.statuses {
+			if providerStatusRank[status] > providerStatusRank[outcomes[code]] {
+				outcomes[code] = status
+			}
+		}
+		// a provider served fresh on one leg and live on another is reported as the worst of the two
+		for code, status := range leg.cacheStatus {
+			if cacheStatusRank[status] > cacheStatusRank[meta.CacheStatus[code]] {
+				meta.CacheStatus[code] = status
+			}
+		}
+
+		for _, code := range leg.skipped {
+			if !skipped[code] {
+				skipped[code] = true
+				meta.ProvidersSkipped = append(meta.ProvidersSkipped, code)
+			}
+		}
+
+		for _, code := range leg.timedOut {
+			if !timedOut[code] {
+				timedOut[code] = true
+				meta.ProvidersTimedOut = append(meta.ProvidersTimedOut, code)
+			}
+		}
+
+		for code, retries := range leg.retries {
+			if meta.ProviderRetries == nil {
+				meta.ProviderRetries = make(map[string]int)
+			}
+			meta.ProviderRetries[code] += retries
+		}
+	}
+
+	// skipped providers have no rank, they were not asked
+	meta.ProvidersQueried = len(outcomes)
+	for _, status := range outcomes {
+		switch status {
+		case entity.PROVIDER_OK:
+			meta.ProvidersSucceeded++
+		case entity.PROVIDER_FAILED:
+			meta.ProvidersFailed++
+		}
+	}
+
+	return meta
 }
 
 func (f *flightService) applySorting(flights []entity.Flight, req entity.SearchRequest) {
@@ -117,7 +315,7 @@ func (f *flightService) applySorting(flights []entity.Flight, req entity.SearchR
 		case "arrival_time":
 			return flights[a].Arrival.Timestamp < flights[b].Arrival.Timestamp
 		default: // Price
-			return flights[a].Price.Amount < flights[b].Price.Amount
+			return flights[a].Price.TotalAmount < flights[b].Price.TotalAmount
 		}
 	})
 }
@@ -133,10 +331,10 @@ func (f *flightService) applyFiltersAndIdentifyBest(flights []entity.Flight, req
 		destMap[strings.ToUpper(d)] = true
 	}
 
-	// constant value
-	const timeWeight = 2500.0
-	const stopPenalty = 150000.0
-	const amenities = 50000.0
+	avoidMap := make(map[string]bool)
+	for _, a := range req.AvoidAirports {
+		avoidMap[strings.ToUpper(a)] = true
+	}
 
 	for _, fl := range flights {
 		if !strings.EqualFold(fl.Departure.Code, req.Origin) || !destMap[strings.ToUpper(fl.Arrival.Code)] {
@@ -144,10 +342,11 @@ func (f *flightService) applyFiltersAndIdentifyBest(flights []entity.Flight, req
 		}
 
 		//  Price, Stop, Duration FILTERS
-		if req.PriceMin > 0 && fl.Price.Amount < req.PriceMin {
+		// the price bounds are for the whole party, like sorting and the best value score
+		if req.PriceMin > 0 && fl.Price.TotalAmount < req.PriceMin {
 			continue
 		}
-		if req.PriceMax > 0 && fl.Price.Amount > req.PriceMax {
+		if req.PriceMax > 0 && fl.Price.TotalAmount > req.PriceMax {
 			continue
 		}
 		if req.MaxStops != nil && fl.Stops > *req.MaxStops {
@@ -157,22 +356,30 @@ func (f *flightService) applyFiltersAndIdentifyBest(flights []entity.Flight, req
 			continue
 		}
 
-		// Time filters
-		depTimeStr := fl.Departure.Datetime.Format("15:04")
-		if req.MinDepTime != "" && depTimeStr < req.MinDepTime {
+		// the whole party has to fit on the flight
+		if fl.AvailableSeats < req.Passanger {
 			continue
 		}
-		if req.MaxDepTime != "" && depTimeStr > req.MaxDepTime {
+
+		// Cabin filter, the request cabin is already canonical
+		if req.CabinClass != "" && fl.CabinClass != req.CabinClass {
 			continue
 		}
 
-		// CALCULATE BEST VALUE SCORE
-		// Formula: Price + (Total Time Weight) + (Stop Penalty) - (Amenities)
-		score := fl.Price.Amount +
-			(float64(fl.Duration.TotalMinutes) * timeWeight) +
-			(float64(fl.Stops) * stopPenalty) -
-			(float64(len(fl.Amenities)) * amenities)
+		// Layover filters
+		if !f.matchesLayoverFilters(fl, req, avoidMap) {
+			continue
+		}
 
+		// Time filters, in the local time of the departure and arrival airport
+		if !entity.InTimeWindow(fl.Departure.LocalTime(), req.MinDepTime, req.MaxDepTime) {
+			continue
+		}
+		if !entity.InTimeWindow(fl.Arrival.LocalTime(), req.MinArrTime, req.MaxArrTime) {
+			continue
+		}
+
+		score := f.bestValueScore(fl)
 		if score < minScore {
 			minScore = score
 			temp := fl
@@ -185,95 +392,335 @@ func (f *flightService) applyFiltersAndIdentifyBest(flights []entity.Flight, req
 	return filtered, bestDeal
 }
 
-func (f *flightService) fetchSpecificAirlines(ctx context.Context, req entity.SearchRequest, missingCodes []string) ([]entity.Flight, int, int) {
+// matchesLayoverFilters checks every layover against the single, total and minimum
+// connection limits and the airports to avoid. A flight with more stops than listed
+// layovers can not be checked and fails as soon as one of these filters is set.
+func (f *flightService) matchesLayoverFilters(fl entity.Flight, req entity.SearchRequest, avoid map[string]bool) bool {
+	filtered := len(avoid) > 0 || req.MaxLayoverMinutes > 0 || req.MinLayoverMinutes > 0 || req.MaxTotalLayoverMinutes > 0
+	if filtered && len(fl.Layovers) < fl.Stops {
+		return false
+	}
+
+	totalLayover := 0
+	for _, layover := range fl.Layovers {
+		if avoid[strings.ToUpper(layover.Airport)] {
+			return false
+		}
+		if req.MaxLayoverMinutes > 0 && layover.DurationMinutes > req.MaxLayoverMinutes {
+			return false
+		}
+		if req.MinLayoverMinutes > 0 && layover.DurationMinutes < req.MinLayoverMinutes {
+			return false
+		}
+		totalLayover += layover.DurationMinutes
+	}
+
+	if req.MaxTotalLayoverMinutes > 0 && totalLayover > req.MaxTotalLayoverMinutes {
+		return false
+	}
+	return true
+}
+
+// bestValueScore is lower for a better deal
+// Formula: Price + (Total Time Weight) + (Stop Penalty) - (Amenities)
+func (f *flightService) bestValueScore(fl entity.Flight) float64 {
+	weights := f.config.BestValue
+
+	// bring the price to rupiah so the weights keep their meaning in any display currency
+	price, err := fx.Convert(f.rates, fl.Price.TotalAmount, fl.Price.Currency, scoreCurrency)
+	if err != nil {
+		price = fl.Price.TotalAmount
+	}
+
+	return price +
+		(float64(fl.Duration.TotalMinutes) * weights.TimePerMinute) +
+		(float64(fl.Stops) * weights.StopPenalty) -
+		(float64(len(fl.Amenities)) * weights.AmenityBonus)
+}
+
+func (f *flightService) fetchSpecificAirlines(ctx context.Context, req entity.SearchRequest, missingCodes []string) fetchResult {
 	var mu sync.Mutex
 	var wg sync.WaitGroup
-	var succeeded, failed int32
 	allFlights := []entity.Flight{}
-	log := logger.Init()
-
-	providerMap := map[string]func(ctx context.Context) ([]entity.Flight, error){
-		entity.GARUDA:   f.garudaService.GetFlight,
-		entity.BATIKAIR: f.batikAirService.GetFlight,
-		entity.LIONAIR:  f.lionAirService.GetFlight,
-		entity.AIRASIA:  f.airAsiaService.GetFlight,
-	}
+	statuses := make(map[string]string)
+	var skipped, timedOut []string
+	retries := make(map[string]int)
 
 	for _, code := range missingCodes {
-		fn, exists := providerMap[code]
+		p, exists := f.providers.Get(code)
 		if !exists {
 			continue
 		}
 
 		wg.Add(1)
-		go func(airlineCode string, fetchFn func(context.Context) ([]entity.Flight, error)) {
+		go func(airlineCode string, p provider.Provider) {
 			defer wg.Done()
 
-			defer func() {
-				if r := recover(); r != nil {
-					log.Errorf("Recovered from panic in %s: %v", airlineCode, r)
-					atomic.AddInt32(&failed, 1)
-				}
-			}()
-
-			res, err := fetchFn(ctx)
-			if err != nil {
-				log.Errorf("API Fetch Failed for %s: %v", airlineCode, err)
-				atomic.AddInt32(&failed, 1)
-				return
-			}
+			res, status := f.fetchShared(ctx, req, p)
 
 			mu.Lock()
-			allFlights = append(allFlights, res...)
-			mu.Unlock()
-			atomic.AddInt32(&succeeded, 1)
+			defer mu.Unlock()
+			if res.retries > 0 {
+				retries[airlineCode] = res.retries
+			}
+			statuses[airlineCode] = status
+			switch status {
+			case entity.PROVIDER_SKIPPED:
+				skipped = append(skipped, airlineCode)
+			case entity.PROVIDER_TIMED_OUT:
+				timedOut = append(timedOut, airlineCode)
+			case entity.PROVIDER_OK:
+				allFlights = append(allFlights, res.flights...)
+			}
+		}(code, p)
+	}
 
-			f.saveToCache(context.Background(), req, airlineCode, res)
+	wg.Wait()
+	return fetchResult{
+		flights:  allFlights,
+		statuses: statuses,
+		skipped:  skipped,
+		timedOut: timedOut,
+		retries:  retries,
+	}
+}
 
-		}(code, fn)
+// fetchShared calls one provider, concurrent misses on the same key wait for a single
+// provider call. The status is one of the entity.PROVIDER_* values.
+func (f *flightService) fetchShared(ctx context.Context, req entity.SearchRequest, p provider.Provider) (providerFetch, string) {
+	res, shared, err := f.inflight.Do(ctx, f.cacheKey(req, p.Code()), func(ctx context.Context) (providerFetch, error) {
+		return f.fetchProvider(ctx, p, req)
+	})
+	if shared {
+		f.logger.DebugContext(ctx, "sharing in-flight fetch", "provider", p.Code())
 	}
 
-	wg.Wait()
-	return allFlights, int(succeeded), int(failed)
+	switch {
+	case res.skipped:
+		return res, entity.PROVIDER_SKIPPED
+	// the search deadline passed while the fetch was still running, it is cancelled
+	// unless another search still waits for it
+	case err != nil && errors.Is(err, context.DeadlineExceeded) && errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return res, entity.PROVIDER_TIMED_OUT
+	case err != nil:
+		return res, entity.PROVIDER_FAILED
+	}
+	return res, entity.PROVIDER_OK
+}
+
+// fetchProvider calls one provider through its circuit breaker and caches the result
+func (f *flightService) fetchProvider(ctx context.Context, p provider.Provider, req entity.SearchRequest) (providerFetch, error) {
+	code := p.Code()
+
+	// a provider that keeps failing is skipped instead of burning its timeout again
+	health := f.providerHealth(code)
+	if !health.breaker.Allow() {
+		f.logger.WarnContext(ctx, "circuit open, skipping provider", "provider", code)
+		return providerFetch{skipped: true}, nil
+	}
+
+	var res providerFetch
+	var err error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("recovered from panic in %s: %v", code, r)
+			}
+		}()
+		res.flights, res.retries, err = f.fetchWithRetry(ctx, p, health, f.providerQuery(req))
+	}()
+
+	// every search waiting for it gave up, that says nothing about the provider
+	if err != nil && errors.Is(ctx.Err(), context.Canceled) {
+		f.logger.DebugContext(ctx, "provider fetch abandoned", "provider", code, "retries", res.retries, "error", err)
+		health.breaker.Release()
+		return res, err
+	}
+	if err != nil {
+		f.logger.ErrorContext(ctx, "provider fetch failed", "provider", code, "retries", res.retries, "error", err)
+		health.breaker.Failure()
+		return res, err
+	}
+	health.breaker.Success()
+	f.logger.DebugContext(ctx, "provider answered", "provider", code, "flights", len(res.flights), "retries", res.retries)
+
+	f.saveToCache(ctx, req, code, res.flights)
+	return res, nil
+}
+
+// fetchWithRetry retries transient failures with jittered backoff inside the provider
+// budget, and hedges each attempt once the provider has a p95 latency on record
+func (f *flightService) fetchWithRetry(ctx context.Context, p provider.Provider, health *providerHealth, query provider.Query) ([]entity.Flight, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, f.config.ProviderBudget)
+	defer cancel()
+
+	call := func(ctx context.Context) ([]entity.Flight, error) {
+		start := time.Now()
+		flights, err := p.GetFlight(ctx, query)
+		if err == nil {
+			health.latency.Observe(time.Since(start))
+		}
+		return flights, err
+	}
+
+	var flights []entity.Flight
+	retried, err := resilience.Retry(ctx, f.config.Retry, func(ctx context.Context) error {
+		var err error
+		if delay, ok := f.config.Hedge.HedgeDelay(health.latency); ok {
+			flights, _, err = resilience.Hedge(ctx, delay, call)
+		} else {
+			flights, err = call(ctx)
+		}
+		return err
+	})
+
+	return flights, retried, err
+}
+
+// providerHealth returns the breaker and latency history of a provider, creating them on first use
+func (f *flightService) providerHealth(code string) *providerHealth {
+	f.healthMu.Lock()
+	defer f.healthMu.Unlock()
+
+	h, ok := f.health[code]
+	if !ok {
+		h = &providerHealth{
+			breaker: resilience.NewCircuitBreaker(f.config.Breaker),
+			latency: resilience.NewLatencyTracker(),
+		}
+		f.health[code] = h
+	}
+	return h
+}
+
+// providerQuery asks for the whole inventory of the route and date, every cabin and
+// every seat count. The party and the cabin are applied by the filters, so one cached
+// answer serves any party and cabin, a fare calendar included.
+func (f *flightService) providerQuery(req entity.SearchRequest) provider.Query {
+	return provider.Query{
+		Origin:        req.Origin,
+		Destinations:  req.Destination,
+		DepartureDate: req.DepartureDate,
+	}
+}
+
+// cacheKey is flights:<origin>:<destinations>:<date>:<airline>, every field of the
+// provider query. In-flight fetches are shared on the same key.
+func (f *flightService) cacheKey(req entity.SearchRequest, code string) string {
+	destinations := append([]string(nil), req.Destination...)
+	sort.Strings(destinations)
+	return fmt.Sprintf("flights:%s:%s:%s:%s",
+		req.Origin, strings.Join(destinations, ","), req.DepartureDate, code)
 }
 
 func (f *flightService) saveToCache(ctx context.Context, req entity.SearchRequest, code string, flights []entity.Flight) {
-	key := fmt.Sprintf("flights:%s:%s:%s", req.Origin, req.DepartureDate, code)
+	key := f.cacheKey(req, code)
+	ttl := f.config.Cache.TTLFor(code)
 
-	err := f.redisService.Set(ctx, key, flights, 1*time.Minute)
+	// redis keeps it for the whole fresh + stale life, freshness is decided on read
+	entry := entity.CachedFlights{
+		Flights:  flights,
+		CachedAt: time.Now(),
+	}
+	err := f.redisService.Set(ctx, key, entry, ttl.Fresh+ttl.Stale)
 	if err != nil {
-		logger.Init().Errorf("Redis Save Failed for %s: %v", code, err)
+		f.logger.WarnContext(ctx, "cache save failed", "provider", code, "key", key, "error", err)
+	}
+}
+
+func newSearchCriteria(req entity.SearchRequest) entity.SearchCriteria {
+	return entity.SearchCriteria{
+		Origin:        req.Origin,
+		Destination:   req.Destination,
+		DepartureDate: req.DepartureDate,
+		ReturnDate:    req.ReturnDate,
+		Passengers:    req.Passanger,
+		Adults:        req.Adults,
+		Children:      req.Children,
+		Infants:       req.Infants,
+		CabinClass:    req.CabinClass,
+		Currency:      req.DisplayCurrency,
 	}
 }
 
 func (f *flightService) standardizeRequest(req *entity.SearchRequest) {
 	req.Origin = strings.ToUpper(req.Origin)
+	// a plain passengers count is a party of adults, one when it is not given either
+	if !req.HasPassengerMix() {
+		req.Adults = max(req.Passanger, 1)
+	}
+	req.Passanger = req.SeatedPassengers()
+	req.DisplayCurrency = f.displayCurrency(req.DisplayCurrency)
 	for i, dest := range req.Destination {
 		req.Destination[i] = strings.ToUpper(strings.TrimSpace(dest))
 	}
+
+	if cabin, ok := entity.NormalizeCabinClass(req.CabinClass); ok {
+		req.CabinClass = cabin
+	}
+
+	// copied, multi-city legs share this slice
+	avoid := make([]string, len(req.AvoidAirports))
+	for i, airport := range req.AvoidAirports {
+		avoid[i] = strings.ToUpper(strings.TrimSpace(airport))
+	}
+	req.AvoidAirports = avoid
 }
 
-func (f *flightService) getCachedAirlines(ctx context.Context, req entity.SearchRequest) ([]entity.Flight, []string, int) {
-	airlines := []string{entity.GARUDA, entity.LIONAIR, entity.BATIKAIR, entity.AIRASIA}
-	targetAirlines := airlines
+// getCachedAirlines serves fresh and stale cache entries and lists the airlines that
+// have to be fetched live. Stale entries are refreshed in the background.
+func (f *flightService) getCachedAirlines(ctx context.Context, req entity.SearchRequest) ([]entity.Flight, []string, map[string]string) {
 	var cachedFlights []entity.Flight
 	var missingAirlines []string
-	var succeeded int
+	cacheStatus := make(map[string]string)
+
+	var staleAirlines []string
+	for _, code := range f.targetAirlines(req) {
+		flights, status := f.readCache(ctx, req, code)
+		f.logger.DebugContext(ctx, "cache lookup", "provider", code, "status", status)
+
+		cacheStatus[code] = status
+		switch status {
+		case entity.CACHE_MISS:
+			missingAirlines = append(missingAirlines, code)
+			continue
+		case entity.CACHE_STALE:
+			staleAirlines = append(staleAirlines, code)
+		}
+		cachedFlights = append(cachedFlights, flights...)
+	}
 
+	if len(staleAirlines) > 0 {
+		// the caller does not wait for this, and coalescing keeps refreshes of the same key to one
+		go f.fetchSpecificAirlines(context.WithoutCancel(ctx), req, staleAirlines)
+	}
+
+	return cachedFlights, missingAirlines, cacheStatus
+}
+
+// targetAirlines are the provider codes a search asks, every registered provider by default
+func (f *flightService) targetAirlines(req entity.SearchRequest) []string {
 	if len(req.Airlines) > 0 {
-		targetAirlines = req.Airlines
+		return req.Airlines
 	}
+	return f.providers.Codes()
+}
 
-	for _, code := range targetAirlines {
-		var airlineFlights []entity.Flight
-		key := fmt.Sprintf("flights:%s:%s:%s", req.Origin, req.DepartureDate, code)
+// readCache returns the cached flights of one provider and whether they are fresh or stale,
+// a missing or expired entry is a miss
+func (f *flightService) readCache(ctx context.Context, req entity.SearchRequest, code string) ([]entity.Flight, string) {
+	var entry entity.CachedFlights
+	if err := f.redisService.Get(ctx, f.cacheKey(req, code), &entry); err != nil {
+		return nil, entity.CACHE_MISS
+	}
 
-		if err := f.redisService.Get(ctx, key, &airlineFlights); err == nil && len(airlineFlights) > 0 {
-			cachedFlights = append(cachedFlights, airlineFlights...)
-			succeeded++
-		} else {
-			missingAirlines = append(missingAirlines, code)
-		}
+	ttl := f.config.Cache.TTLFor(code)
+	age := time.Since(entry.CachedAt)
+	switch {
+	case age <= ttl.Fresh:
+		return entry.Flights, entity.CACHE_FRESH
+	case age <= ttl.Fresh+ttl.Stale:
+		return entry.Flights, entity.CACHE_STALE
 	}
-	return cachedFlights, missingAirlines, succeeded
+	return nil, entity.CACHE_MISS
 }
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"flight-aggregator/internal/entity"
+	"slices"
+	"testing"
+)
+
+func TestPriceFiltersUseThePartyTotal(t *testing.T) {
+	f := newTestService(t, newMemCache())
+
+	tests := []struct {
+		name     string
+		adults   int
+		priceMin float64
+		priceMax float64
+		want     []string
+	}{
+		{"one adult, per seat and total agree", 1, 0, 1000000, []string{"CHEAP", "MID"}},
+		{"the max bounds the party total", 4, 0, 2000000, []string{"CHEAP"}},
+		{"the min bounds the party total", 4, 2000000, 0, []string{"MID", "DEAR"}},
+		{"a window on the total", 2, 1000000, 1500000, []string{"MID"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flights := []entity.Flight{
+				testFlight("CHEAP", "Garuda Indonesia", 450000),
+				testFlight("MID", "Garuda Indonesia", 600000),
+				testFlight("DEAR", "Garuda Indonesia", 1200000),
+			}
+			req := entity.SearchRequest{
+				Origin:      "CGK",
+				Destination: []string{"DPS"},
+				Adults:      tt.adults,
+				PriceMin:    tt.priceMin,
+				PriceMax:    tt.priceMax,
+			}
+			f.standardizeRequest(&req)
+
+			got, _ := f.prepareFlights(context.Background(), flights, req)
+			if ids := flightIDs(got); !slices.Equal(ids, tt.want) {
+				t.Fatalf("flights = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchesLayoverFilters(t *testing.T) {
+	f := newTestService(t, newMemCache())
+
+	oneStop := testFlight("GA1", "Garuda Indonesia", 1000000)
+	oneStop.Stops = 1
+	oneStop.Layovers = []entity.Layover{{Airport: "SUB", DurationMinutes: 90}}
+
+	// the airline says one stop but sends no layover
+	unlisted := oneStop
+	unlisted.Layovers = nil
+
+	tests := []struct {
+		name   string
+		flight entity.Flight
+		req    entity.SearchRequest
+		avoid  []string
+		want   bool
+	}{
+		{"no filter keeps everything", unlisted, entity.SearchRequest{}, nil, true},
+		{"direct flights always pass", testFlight("GA2", "Garuda Indonesia", 1000000), entity.SearchRequest{MaxLayoverMinutes: 30}, []string{"SUB"}, true},
+		{"within the single limit", oneStop, entity.SearchRequest{MaxLayoverMinutes: 120}, nil, true},
+		{"over the single limit", oneStop, entity.SearchRequest{MaxLayoverMinutes: 60}, nil, false},
+		{"tighter than the minimum", oneStop, entity.SearchRequest{MinLayoverMinutes: 120}, nil, false},
+		{"over the total limit", oneStop, entity.SearchRequest{MaxTotalLayoverMinutes: 60}, nil, false},
+		{"connects through an avoided airport", oneStop, entity.SearchRequest{}, []string{"SUB"}, false},
+		{"unlisted layover fails the single limit", unlisted, entity.SearchRequest{MaxLayoverMinutes: 120}, nil, false},
+		{"unlisted layover fails the minimum", unlisted, entity.SearchRequest{MinLayoverMinutes: 30}, nil, false},
+		{"unlisted layover fails the total limit", unlisted, entity.SearchRequest{MaxTotalLayoverMinutes: 120}, nil, false},
+		{"unlisted layover fails the avoided airports", unlisted, entity.SearchRequest{}, []string{"UPG"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			avoid := make(map[string]bool)
+			for _, airport := range tt.avoid {
+				avoid[airport] = true
+			}
+			if got := f.matchesLayoverFilters(tt.flight, tt.req, avoid); got != tt.want {
+				t.Fatalf("matchesLayoverFilters = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeatFilterFitsTheSeatedParty(t *testing.T) {
+	f := newTestService(t, newMemCache())
+
+	tests := []struct {
+		name                      string
+		passengers                int
+		adults, children, infants int
+		want                      []string
+	}{
+		{"default one passenger", 0, 0, 0, 0, []string{"ONE", "THREE", "FOUR"}},
+		{"passengers count", 3, 0, 0, 0, []string{"THREE", "FOUR"}},
+		{"adults and children take a seat", 0, 2, 2, 0, []string{"FOUR"}},
+		{"infants sit on a lap", 0, 2, 0, 2, []string{"THREE", "FOUR"}},
+		{"larger than every flight", 5, 0, 0, 0, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var flights []entity.Flight
+			for _, seats := range []struct {
+				id    string
+				seats int
+			}{{"ONE", 1}, {"THREE", 3}, {"FOUR", 4}} {
+				fl := testFlight(seats.id, "Garuda Indonesia", 1000000)
+				fl.AvailableSeats = seats.seats
+				flights = append(flights, fl)
+			}
+			req := entity.SearchRequest{
+				Origin:      "CGK",
+				Destination: []string{"DPS"},
+				Passanger:   tt.passengers,
+				Adults:      tt.adults,
+				Children:    tt.children,
+				Infants:     tt.infants,
+			}
+			f.standardizeRequest(&req)
+
+			got, _ := f.prepareFlights(context.Background(), flights, req)
+			if ids := flightIDs(got); !slices.Equal(ids, tt.want) {
+				t.Fatalf("flights = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package fx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var ErrUnknownCurrency = errors.New("unknown currency")
+
+// RateSource converts between currencies. The table below reads a local file,
+// a live rates API only has to implement this interface.
+type RateSource interface {
+	// Rate is how many units of to one unit of from is worth
+	Rate(from, to string) (float64, error)
+	Supports(currency string) bool
+}
+
+// Convert returns amount in currency to, using rates from source
+func Convert(source RateSource, amount float64, from, to string) (float64, error) {
+	if from == to {
+		return amount, nil
+	}
+	rate, err := source.Rate(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return amount * rate, nil
+}
+
+// tableFile is the layout of the rate table on disk. Every rate is the value of
+// one unit of the currency in the base currency, e.g. {"base": "IDR", "rates": {"USD": 16000}}.
+type tableFile struct {
+	Base      string             `json:"base"`
+	UpdatedAt string             `json:"updated_at"`
+	Rates     map[string]float64 `json:"rates"`
+}
+
+type table struct {
+	base  string
+	rates map[string]float64
+}
+
+// LoadTable reads a JSON rate table
+func LoadTable(path string) (RateSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read fx rates %s: %w", path, err)
+	}
+
+	var file tableFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse fx rates %s: %w", path, err)
+	}
+
+	return NewTable(file.Base, file.Rates)
+}
+
+// NewTable builds a rate source from rates expressed in the base currency
+func NewTable(base string, rates map[string]float64) (RateSource, error) {
+	base = strings.ToUpper(base)
+	if len(base) != 3 {
+		return nil, fmt.Errorf("fx base currency %q must be a 3-letter ISO code", base)
+	}
+
+	t := &table{base: base, rates: map[string]float64{base: 1}}
+	for currency, rate := range rates {
+		if rate <= 0 {
+			return nil, fmt.Errorf("fx rate of %s must be greater than zero", currency)
+		}
+		t.rates[strings.ToUpper(currency)] = rate
+	}
+	return t, nil
+}
+
+func (t *table) Supports(currency string) bool {
+	_, ok := t.rates[currency]
+	return ok
+}
+
+func (t *table) Rate(from, to string) (float64, error) {
+	fromRate, ok := t.rates[from]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownCurrency, from)
+	}
+	toRate, ok := t.rates[to]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownCurrency, to)
+	}
+	return fromRate / toRate, nil
+}
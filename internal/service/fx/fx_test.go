@@ -0,0 +1,108 @@
+package fx
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewTable(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    string
+		rates   map[string]float64
+		wantErr bool
+	}{
+		{"rates in the base", "IDR", map[string]float64{"USD": 16000}, false},
+		{"lower case codes", "idr", map[string]float64{"usd": 16000}, false},
+		{"base is not a code", "RUPIAH", nil, true},
+		{"zero rate", "IDR", map[string]float64{"USD": 0}, true},
+		{"negative rate", "IDR", map[string]float64{"USD": -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := NewTable(tt.base, tt.rates)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewTable error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && (!source.Supports("IDR") || !source.Supports("USD")) {
+				t.Fatalf("table does not support IDR and USD")
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	source, err := NewTable("IDR", map[string]float64{"USD": 16000, "SGD": 12000, "JPY": 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		amount   float64
+		from, to string
+		want     float64
+		wantErr  error
+	}{
+		{"same currency", 1250000, "IDR", "IDR", 1250000, nil},
+		{"from the base", 1600000, "IDR", "USD", 100, nil},
+		{"to the base", 100, "USD", "IDR", 1600000, nil},
+		{"through the base", 100, "USD", "SGD", 133.33333333, nil},
+		{"no minor unit", 12, "SGD", "JPY", 1440, nil},
+		{"unknown source", 100, "KRW", "IDR", 0, ErrUnknownCurrency},
+		{"unknown target", 100, "IDR", "KRW", 0, ErrUnknownCurrency},
+		// a currency converted to itself needs no rate
+		{"unknown but the same", 100, "KRW", "KRW", 100, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(source, tt.amount, tt.from, tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Convert error = %v, want %v", err, tt.wantErr)
+			}
+			if math.Abs(got-tt.want) > 1e-6 {
+				t.Fatalf("Convert = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadTable(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{"the shipped table", filepath.Join("..", "..", "..", "data", "fx_rates.json"), false},
+		{"a valid table", write("ok.json", `{"base": "IDR", "rates": {"USD": 16000}}`), false},
+		{"not json", write("bad.json", `base: IDR`), true},
+		{"a bad rate", write("zero.json", `{"base": "IDR", "rates": {"USD": 0}}`), true},
+		{"missing file", filepath.Join(dir, "nope.json"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := LoadTable(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadTable error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && !source.Supports("USD") {
+				t.Fatal("loaded table does not support USD")
+			}
+		})
+	}
+}
@@ -1,14 +1,15 @@
 package garuda
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
-	logger "flight-aggregator/internal/common"
 	"flight-aggregator/internal/common/util"
 	"flight-aggregator/internal/entity"
+	"flight-aggregator/internal/service/provider"
 	"fmt"
+	"log/slog"
 	"math/rand"
-	"os"
 	"time"
 )
 
@@ -17,24 +18,60 @@ type GarudaResponse struct {
 	Flights []entity.GarudaFlight `json:"flights"`
 }
 
-type garudaService struct {
-	filePath string
+// Code and Name identify this airline in the provider registry
+const (
+	Code = "Garuda"
+	Name = "Garuda Indonesia"
+)
+
+func init() {
+	provider.Register(Code, NewGarudaService)
 }
 
-type GarudaService interface {
-	GetFlight(ctx context.Context) ([]entity.Flight, error)
+// cabinClasses maps Garuda fare_class values, which are cabin names or booking classes
+var cabinClasses = map[string]string{
+	"ECONOMY": entity.CABIN_ECONOMY,
+	"Y":       entity.CABIN_ECONOMY,
+	"B":       entity.CABIN_ECONOMY,
+	"M":       entity.CABIN_ECONOMY,
+	"K":       entity.CABIN_ECONOMY,
+	"C":       entity.CABIN_BUSINESS,
+	"D":       entity.CABIN_BUSINESS,
+	"I":       entity.CABIN_BUSINESS,
+	"J":       entity.CABIN_BUSINESS,
+	"F":       entity.CABIN_FIRST,
+	"A":       entity.CABIN_FIRST,
 }
 
-func NewGarudaService(path string) GarudaService {
+type garudaService struct {
+	fixtureDir string
+	timeout    time.Duration
+	airports   entity.AirportLookup
+	logger     *slog.Logger
+}
+
+// NewGarudaService reads mock responses from opts.FixtureDir, one file per route and date.
+// Airport names, cities and timezones come from opts.Airports.
+func NewGarudaService(opts provider.Options) provider.Provider {
 	return &garudaService{
-		filePath: path,
+		fixtureDir: opts.FixtureDir,
+		timeout:    opts.TimeoutOrDefault(),
+		airports:   opts.Airports,
+		logger:     opts.LoggerOrDefault().With("provider", Code),
 	}
 }
 
-// assuming it had 15 December as mock param
-func (g *garudaService) GetFlight(ctx context.Context) ([]entity.Flight, error) {
-	// 1. Set the 2-second deadline
-	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+func (g *garudaService) Code() string {
+	return Code
+}
+
+func (g *garudaService) Name() string {
+	return Name
+}
+
+func (g *garudaService) GetFlight(ctx context.Context, query provider.Query) ([]entity.Flight, error) {
+	// 1. Set the deadline, its cause tells it apart from a search that gave up
+	ctx, cancel := context.WithTimeoutCause(ctx, g.timeout, fmt.Errorf("Garuda fetch timed out after %s", g.timeout))
 	defer cancel()
 
 	type result struct {
@@ -49,19 +86,23 @@ func (g *garudaService) GetFlight(ctx context.Context) ([]entity.Flight, error)
 		time.Sleep(delay)
 		// time.Sleep(5 * time.Second)
 
-		data, err := os.ReadFile(g.filePath)
+		files, err := provider.ReadFixtures(g.fixtureDir, query)
 		if err != nil {
 			resChan <- result{nil, fmt.Errorf("Garuda.getFlight: Failed to read file")}
 			return
 		}
 
-		var garudaResponse GarudaResponse
-		if err := json.Unmarshal(data, &garudaResponse); err != nil {
-			resChan <- result{nil, err}
-			return
+		var rawFlights []entity.GarudaFlight
+		for _, data := range files {
+			var garudaResponse GarudaResponse
+			if err := json.Unmarshal(data, &garudaResponse); err != nil {
+				resChan <- result{nil, err}
+				return
+			}
+			rawFlights = append(rawFlights, garudaResponse.Flights...)
 		}
 
-		flights, err := g.mapFlights(garudaResponse.Flights)
+		flights, err := g.mapFlights(ctx, rawFlights)
 		resChan <- result{flights, err}
 	}()
 
@@ -69,12 +110,11 @@ func (g *garudaService) GetFlight(ctx context.Context) ([]entity.Flight, error)
 	case res := <-resChan:
 		return res.flights, res.err
 	case <-ctx.Done():
-		return nil, fmt.Errorf("Garuda fetch timed out after 2s")
+		return nil, context.Cause(ctx)
 	}
 }
 
-func (g *garudaService) mapFlights(rawFlights []entity.GarudaFlight) ([]entity.Flight, error) {
-	log := logger.Init()
+func (g *garudaService) mapFlights(ctx context.Context, rawFlights []entity.GarudaFlight) ([]entity.Flight, error) {
 	if len(rawFlights) == 0 {
 		return []entity.Flight{}, nil
 	}
@@ -84,13 +124,13 @@ func (g *garudaService) mapFlights(rawFlights []entity.GarudaFlight) ([]entity.F
 	for _, raw := range rawFlights {
 
 		if err := raw.Validate(); err != nil {
-			log.Errorf("Garuda Data Integrity Error: %v", err)
+			g.logger.WarnContext(ctx, "dropping flight that fails validation", "flight", raw.FlightID, "error", err)
 			continue
 		}
 
-		unified, err := g.mapFlight(raw)
+		unified, err := g.mapFlight(ctx, raw)
 		if err != nil {
-			log.Errorf("Error Garuda.mapFlights: Corrupted data")
+			g.logger.WarnContext(ctx, "dropping flight that can not be mapped", "flight", raw.FlightID, "error", err)
 			continue
 		}
 		unifiedFlights = append(unifiedFlights, unified)
@@ -99,13 +139,16 @@ func (g *garudaService) mapFlights(rawFlights []entity.GarudaFlight) ([]entity.F
 	return unifiedFlights, nil
 }
 
-func (g *garudaService) mapFlight(flight entity.GarudaFlight) (entity.Flight, error) {
-	// ToDo: handle WIT, WIB, WITA
-	depTime, err := time.Parse(time.RFC3339, flight.Departure.Time)
+func (g *garudaService) mapFlight(ctx context.Context, flight entity.GarudaFlight) (entity.Flight, error) {
+	locationRegistery := entity.NewLocationRegistry(g.airports)
+
+	// times are kept in the airport's own zone (WIB, WITA or WIT), Garuda may
+	// send them with an offset or as plain local time
+	depTime, err := locationRegistery.ParseLocalTime(flight.Departure.Airport, flight.Departure.Time)
 	if err != nil {
 		return entity.Flight{}, err
 	}
-	arrTime, err := time.Parse(time.RFC3339, flight.Arrival.Time)
+	arrTime, err := locationRegistery.ParseLocalTime(flight.Arrival.Airport, flight.Arrival.Time)
 	if err != nil {
 		return entity.Flight{}, err
 	}
@@ -116,19 +159,11 @@ func (g *garudaService) mapFlight(flight entity.GarudaFlight) (entity.Flight, er
 	mins := totalMinutes % 60
 	formattedDuration := fmt.Sprintf("%dh %dm", hours, mins)
 
-	// init location registery
-	locationRegistery := entity.LocationRegistry{}
-
-	// formated IDR
-	const idr = "IDR"
-	var formattedPrice string
-	if flight.Price.Currency == idr {
-		formattedPrice = util.FormatIDR(float64(flight.Price.Amount))
-	}
+	layovers := g.mapLayovers(ctx, flight, locationRegistery)
 
 	return entity.Flight{
-		ID:       fmt.Sprintf("%s_%s", flight.FlightID, entity.GARUDA),
-		Provider: entity.PROVIDER_GARUDA,
+		ID:       fmt.Sprintf("%s_%s", flight.FlightID, Code),
+		Provider: Name,
 		Airline: entity.AirlineInfo{
 			Name: flight.Airline,
 			Code: flight.AirlineCode,
@@ -136,30 +171,35 @@ func (g *garudaService) mapFlight(flight entity.GarudaFlight) (entity.Flight, er
 		FlightNumber: flight.FlightID,
 		Departure: entity.LocationDetails{
 			Airport:   locationRegistery.GetAirport(flight.Departure.Airport),
-			City:      flight.Departure.City,
+			City:      cmp.Or(locationRegistery.GetCity(flight.Departure.Airport), flight.Departure.City),
 			Datetime:  depTime,
 			Timestamp: depTime.Unix(),
+			Timezone:  locationRegistery.GetTimezone(flight.Departure.Airport),
 			Code:      flight.Departure.Airport,
 		},
 		Arrival: entity.LocationDetails{
 			Airport:   locationRegistery.GetAirport(flight.Arrival.Airport),
-			City:      flight.Arrival.City,
+			City:      cmp.Or(locationRegistery.GetCity(flight.Arrival.Airport), flight.Arrival.City),
 			Datetime:  arrTime,
 			Timestamp: arrTime.Unix(),
+			Timezone:  locationRegistery.GetTimezone(flight.Arrival.Airport),
 			Code:      flight.Arrival.Airport,
 		},
 		Duration: entity.DurationDetails{
 			TotalMinutes: totalMinutes,
 			Formatted:    formattedDuration,
 		},
-		Stops: flight.Stops,
+		Stops:    flight.Stops,
+		Layovers: layovers,
 		Price: entity.PriceDetails{
 			Amount:    flight.Price.Amount,
 			Currency:  flight.Price.Currency,
-			Formatted: formattedPrice,
+			Formatted: util.FormatMoney(flight.Price.Amount, flight.Price.Currency),
+			Breakdown: entity.TotalOnlyFare(),
 		},
 		AvailableSeats: flight.AvailableSeats,
-		CabinClass:     flight.FareClass,
+		CabinClass:     entity.MapCabinClass(cabinClasses, flight.FareClass),
+		FareClass:      flight.FareClass,
 		Aircraft:       &flight.Aircraft,
 		Amenities:      flight.Amenities,
 		Baggage: entity.BaggageDetails{
@@ -168,3 +208,46 @@ func (g *garudaService) mapFlight(flight entity.GarudaFlight) (entity.Flight, er
 		},
 	}, nil
 }
+
+// mapLayovers reads the connections between Garuda segments, which carry the
+// landing and take-off time of each connection
+func (g *garudaService) mapLayovers(ctx context.Context, flight entity.GarudaFlight, locationRegistery entity.LocationRegistry) []entity.Layover {
+	layovers := []entity.Layover{}
+	segments := flight.Segments
+	if len(segments) < 2 {
+		return layovers
+	}
+
+	// segments that do not end where the flight lands can not be trusted
+	if segments[len(segments)-1].Arrival.Airport != flight.Arrival.Airport {
+		g.logger.WarnContext(ctx, "ignoring layovers, segments do not end where the flight lands", "flight", flight.FlightID,
+			"segments_end", segments[len(segments)-1].Arrival.Airport, "arrival", flight.Arrival.Airport)
+		return layovers
+	}
+
+	for i := 1; i < len(segments); i++ {
+		airport := segments[i].Departure.Airport
+		layover := entity.Layover{
+			Airport:         airport,
+			AirportName:     locationRegistery.GetAirport(airport),
+			City:            locationRegistery.GetCity(airport),
+			DurationMinutes: segments[i].LayoverMinutes,
+		}
+
+		arrTime, arrErr := locationRegistery.ParseLocalTime(airport, segments[i-1].Arrival.Time)
+		depTime, depErr := locationRegistery.ParseLocalTime(airport, segments[i].Departure.Time)
+		if arrErr == nil {
+			layover.ArrivalTime = &arrTime
+		}
+		if depErr == nil {
+			layover.DepartureTime = &depTime
+		}
+		if layover.DurationMinutes == 0 && arrErr == nil && depErr == nil {
+			layover.DurationMinutes = int(depTime.Sub(arrTime).Minutes())
+		}
+
+		layovers = append(layovers, layover)
+	}
+
+	return layovers
+}
@@ -0,0 +1,208 @@
+package garuda
+
+import (
+	"context"
+	"flight-aggregator/internal/entity"
+	"flight-aggregator/internal/service/provider"
+	"log/slog"
+	"testing"
+)
+
+// airports is a fixed AirportLookup without KOE
+type airports map[string]entity.Airport
+
+func (a airports) Get(code string) (entity.Airport, bool) {
+	airport, ok := a[code]
+	return airport, ok
+}
+
+func newTestService() *garudaService {
+	return NewGarudaService(provider.Options{
+		Airports: airports{
+			"CGK": {Code: "CGK", Name: "Soekarno-Hatta International", City: "Jakarta", Timezone: "Asia/Jakarta"},
+			"DPS": {Code: "DPS", Name: "I Gusti Ngurah Rai International", City: "Denpasar", Timezone: "Asia/Makassar"},
+			"SUB": {Code: "SUB", Name: "Juanda International", City: "Surabaya", Timezone: "Asia/Jakarta"},
+		},
+		Logger: slog.New(slog.DiscardHandler),
+	}).(*garudaService)
+}
+
+func testFlight() entity.GarudaFlight {
+	return entity.GarudaFlight{
+		FlightID:    "GA400",
+		Airline:     "Garuda Indonesia",
+		AirlineCode: "GA",
+		Departure:   entity.Departure{Airport: "CGK", City: "Jakarta", Time: "2025-12-15T06:00:00+07:00"},
+		Arrival:     entity.Arrival{Airport: "DPS", City: "Denpasar", Time: "2025-12-15T11:00:00+08:00"},
+		Price:       entity.Price{Amount: 1250000, Currency: "IDR"},
+		FareClass:   "economy",
+	}
+}
+
+// segment flies from one airport to another, with the times as Garuda sends them
+func segment(from, departs, to, arrives string, layover int) entity.GarudaSegment {
+	return entity.GarudaSegment{
+		Departure:      entity.GarudaSegmentPoint{Airport: from, Time: departs},
+		Arrival:        entity.GarudaSegmentPoint{Airport: to, Time: arrives},
+		LayoverMinutes: layover,
+	}
+}
+
+func TestMapFlightCabinClass(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"economy", entity.CABIN_ECONOMY},
+		{"Y", entity.CABIN_ECONOMY},
+		{"k", entity.CABIN_ECONOMY},
+		{"J", entity.CABIN_BUSINESS},
+		{"Business", entity.CABIN_BUSINESS},
+		{"F", entity.CABIN_FIRST},
+		{"premium economy", entity.CABIN_PREMIUM_ECONOMY},
+		{"Z", entity.CABIN_UNKNOWN},
+	}
+
+	g := newTestService()
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			raw := testFlight()
+			raw.FareClass = tt.raw
+
+			fl, err := g.mapFlight(context.Background(), raw)
+			if err != nil {
+				t.Fatalf("mapFlight: %v", err)
+			}
+			if fl.CabinClass != tt.want || fl.FareClass != tt.raw {
+				t.Errorf("cabin = %q, fare class %q, want %q and the raw %q", fl.CabinClass, fl.FareClass, tt.want, tt.raw)
+			}
+		})
+	}
+}
+
+func TestMapLayovers(t *testing.T) {
+	// want is one layover, its local landing and take-off clock is checked too
+	type want struct {
+		airport, name, city string
+		minutes             int
+		lands, leaves       string
+	}
+
+	tests := []struct {
+		name     string
+		segments []entity.GarudaSegment
+		want     []want
+	}{
+		{
+			name:     "one segment has no connection",
+			segments: []entity.GarudaSegment{segment("CGK", "2025-12-15T06:00:00", "DPS", "2025-12-15T09:00:00", 0)},
+		},
+		{
+			name: "the layover minutes Garuda sends are kept",
+			segments: []entity.GarudaSegment{
+				segment("CGK", "2025-12-15T06:00:00", "SUB", "2025-12-15T07:30:00", 0),
+				segment("SUB", "2025-12-15T08:45:00", "DPS", "2025-12-15T11:00:00", 70),
+			},
+			want: []want{{"SUB", "Juanda International", "Surabaya", 70, "07:30", "08:45"}},
+		},
+		{
+			name: "without layover minutes the wait is taken from the times",
+			segments: []entity.GarudaSegment{
+				segment("CGK", "2025-12-15T06:00:00", "SUB", "2025-12-15T07:30:00", 0),
+				segment("SUB", "2025-12-15T08:45:00", "DPS", "2025-12-15T11:00:00", 0),
+			},
+			want: []want{{"SUB", "Juanda International", "Surabaya", 75, "07:30", "08:45"}},
+		},
+		{
+			name: "times with an offset are read in the connecting airport's zone",
+			segments: []entity.GarudaSegment{
+				segment("CGK", "2025-12-15T06:00:00+07:00", "SUB", "2025-12-15T08:30:00+08:00", 0),
+				segment("SUB", "2025-12-15T08:45:00+07:00", "DPS", "2025-12-15T11:00:00+08:00", 0),
+			},
+			want: []want{{"SUB", "Juanda International", "Surabaya", 75, "07:30", "08:45"}},
+		},
+		{
+			name: "an airport missing from the registry keeps its code",
+			segments: []entity.GarudaSegment{
+				segment("CGK", "2025-12-15T06:00:00+07:00", "KOE", "2025-12-15T09:00:00+08:00", 0),
+				segment("KOE", "2025-12-15T10:00:00+08:00", "DPS", "2025-12-15T11:00:00+08:00", 0),
+			},
+			want: []want{{"KOE", "", "", 60, "09:00", "10:00"}},
+		},
+		{
+			name: "segments that do not end where the flight lands are ignored",
+			segments: []entity.GarudaSegment{
+				segment("CGK", "2025-12-15T06:00:00", "SUB", "2025-12-15T07:30:00", 0),
+				segment("SUB", "2025-12-15T08:45:00", "UPG", "2025-12-15T11:00:00", 60),
+			},
+		},
+	}
+
+	g := newTestService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := testFlight()
+			raw.Segments = tt.segments
+
+			layovers := g.mapLayovers(context.Background(), raw, entity.NewLocationRegistry(g.airports))
+			if len(layovers) != len(tt.want) {
+				t.Fatalf("layovers = %+v, want %d", layovers, len(tt.want))
+			}
+			for i, l := range layovers {
+				w := tt.want[i]
+				if l.Airport != w.airport || l.AirportName != w.name || l.City != w.city || l.DurationMinutes != w.minutes {
+					t.Errorf("layover %d = %s %q %q %d minutes, want %s %q %q %d", i,
+						l.Airport, l.AirportName, l.City, l.DurationMinutes, w.airport, w.name, w.city, w.minutes)
+				}
+				if l.ArrivalTime == nil || l.DepartureTime == nil {
+					t.Fatalf("layover %d has no times", i)
+				}
+				if lands, leaves := l.ArrivalTime.Format("15:04"), l.DepartureTime.Format("15:04"); lands != w.lands || leaves != w.leaves {
+					t.Errorf("layover %d lands %s and leaves %s, want %s and %s", i, lands, leaves, w.lands, w.leaves)
+				}
+			}
+		})
+	}
+}
+
+func TestMapFlightLocalTimes(t *testing.T) {
+	tests := []struct {
+		name             string
+		departs, arrives string
+		wantDep, wantArr string
+		wantTotalMinutes int
+	}{
+		{"with offsets", "2025-12-15T06:00:00+07:00", "2025-12-15T11:00:00+08:00", "06:00", "11:00", 240},
+		{"plain local time", "2025-12-15T06:00:00", "2025-12-15T11:00:00", "06:00", "11:00", 240},
+		{"an offset in another zone is moved to the airport's", "2025-12-14T23:00:00Z", "2025-12-15T03:00:00Z", "06:00", "11:00", 240},
+	}
+
+	g := newTestService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := testFlight()
+			raw.Departure.Time, raw.Arrival.Time = tt.departs, tt.arrives
+
+			fl, err := g.mapFlight(context.Background(), raw)
+			if err != nil {
+				t.Fatalf("mapFlight: %v", err)
+			}
+			if dep, arr := fl.Departure.Datetime.Format("15:04"), fl.Arrival.Datetime.Format("15:04"); dep != tt.wantDep || arr != tt.wantArr {
+				t.Errorf("times = %s to %s, want %s to %s", dep, arr, tt.wantDep, tt.wantArr)
+			}
+			if fl.Departure.Timezone != "Asia/Jakarta" || fl.Arrival.Timezone != "Asia/Makassar" {
+				t.Errorf("zones = %s to %s, want Asia/Jakarta to Asia/Makassar", fl.Departure.Timezone, fl.Arrival.Timezone)
+			}
+			if fl.Duration.TotalMinutes != tt.wantTotalMinutes {
+				t.Errorf("duration = %d minutes, want %d", fl.Duration.TotalMinutes, tt.wantTotalMinutes)
+			}
+		})
+	}
+
+	// a plain time at an airport the registry does not know can not be placed
+	raw := testFlight()
+	raw.Arrival.Airport, raw.Arrival.Time = "KOE", "2025-12-15T11:00:00"
+	if _, err := g.mapFlight(context.Background(), raw); err == nil {
+		t.Error("mapFlight read a plain time at an unknown airport")
+	}
+}
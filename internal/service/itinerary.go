@@ -0,0 +1,77 @@
+package service
+
+import (
+	"flight-aggregator/internal/common/util"
+	"flight-aggregator/internal/entity"
+	"fmt"
+	"math"
+)
+
+// pickRoundTrips pairs every outbound flight with every inbound flight that leaves
+// after it lands and returns the cheapest and the best value combination
+func (f *flightService) pickRoundTrips(outbound, inbound []entity.Flight) (*entity.Itinerary, *entity.Itinerary) {
+	var cheapest, bestValue *entity.Itinerary
+	minPrice, minScore := math.MaxFloat64, math.MaxFloat64
+
+	for _, out := range outbound {
+		for _, in := range inbound {
+			if in.Departure.Timestamp <= out.Arrival.Timestamp {
+				continue
+			}
+
+			price := out.Price.TotalAmount + in.Price.TotalAmount
+			if price < minPrice {
+				minPrice = price
+				itinerary := buildItinerary(out, in)
+				cheapest = &itinerary
+			}
+
+			score := f.bestValueScore(out) + f.bestValueScore(in)
+			if score < minScore {
+				minScore = score
+				itinerary := buildItinerary(out, in)
+				bestValue = &itinerary
+			}
+		}
+	}
+
+	return cheapest, bestValue
+}
+
+// buildItinerary totals the price and the flying time of the given legs
+func buildItinerary(legs ...entity.Flight) entity.Itinerary {
+	itinerary := entity.Itinerary{Legs: legs}
+	if len(legs) == 0 {
+		return itinerary
+	}
+
+	var amount, total float64
+	var totalMinutes int
+	breakdowns := make([]*entity.FareBreakdown, len(legs))
+	for i, leg := range legs {
+		breakdowns[i] = leg.Price.Breakdown
+		amount += leg.Price.Amount
+		total += leg.Price.TotalAmount
+		totalMinutes += leg.Duration.TotalMinutes
+	}
+
+	currency := legs[0].Price.Currency
+	amount = util.RoundMoney(amount, currency)
+	total = util.RoundMoney(total, currency)
+	itinerary.TotalPrice = entity.PriceDetails{
+		Amount:         amount,
+		Currency:       currency,
+		Formatted:      util.FormatMoney(amount, currency),
+		Passengers:     legs[0].Price.Passengers,
+		TotalAmount:    total,
+		TotalFormatted: util.FormatMoney(total, currency),
+		Breakdown:      entity.SumFareBreakdowns(breakdowns...),
+		PassengerFares: mergePassengerFares(legs),
+	}
+	itinerary.TotalDuration = entity.DurationDetails{
+		TotalMinutes: totalMinutes,
+		Formatted:    fmt.Sprintf("%dh %dm", totalMinutes/60, totalMinutes%60),
+	}
+
+	return itinerary
+}
@@ -1,14 +1,15 @@
 package lionair
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
-	logger "flight-aggregator/internal/common"
 	"flight-aggregator/internal/common/util"
 	"flight-aggregator/internal/entity"
+	"flight-aggregator/internal/service/provider"
 	"fmt"
+	"log/slog"
 	"math/rand"
-	"os"
 	"time"
 )
 
@@ -21,23 +22,52 @@ type LionData struct {
 	AvailableFlights []entity.LionFlight `json:"available_flights"`
 }
 
-type lionAirService struct {
-	filePath string
+// Code and Name identify this airline in the provider registry
+const (
+	Code = "LionAir"
+	Name = "Lion Air"
+)
+
+func init() {
+	provider.Register(Code, NewLionAirService)
 }
 
-type LionAirService interface {
-	GetFlight(ctx context.Context) ([]entity.Flight, error)
+// cabinClasses maps Lion Air fare_type values
+var cabinClasses = map[string]string{
+	"ECONOMY":       entity.CABIN_ECONOMY,
+	"PROMO":         entity.CABIN_ECONOMY,
+	"ECONOMY_PROMO": entity.CABIN_ECONOMY,
+	"BUSINESS":      entity.CABIN_BUSINESS,
+}
+
+type lionAirService struct {
+	fixtureDir string
+	timeout    time.Duration
+	airports   entity.AirportLookup
+	logger     *slog.Logger
 }
 
-func NewLionAirService(path string) LionAirService {
+// NewLionAirService reads mock responses from opts.FixtureDir, one file per route and date.
+// Airport names, cities and timezones come from opts.Airports.
+func NewLionAirService(opts provider.Options) provider.Provider {
 	return &lionAirService{
-		filePath: path,
+		fixtureDir: opts.FixtureDir,
+		timeout:    opts.TimeoutOrDefault(),
+		airports:   opts.Airports,
+		logger:     opts.LoggerOrDefault().With("provider", Code),
 	}
 }
 
-// assuming it had 15 December as mock param
-func (g *lionAirService) GetFlight(ctx context.Context) ([]entity.Flight, error) {
-	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+func (g *lionAirService) Code() string {
+	return Code
+}
+
+func (g *lionAirService) Name() string {
+	return Name
+}
+
+func (g *lionAirService) GetFlight(ctx context.Context, query provider.Query) ([]entity.Flight, error) {
+	ctx, cancel := context.WithTimeoutCause(ctx, g.timeout, fmt.Errorf("LionAir fetch timed out after %s", g.timeout))
 	defer cancel()
 
 	type result struct {
@@ -52,19 +82,23 @@ func (g *lionAirService) GetFlight(ctx context.Context) ([]entity.Flight, error)
 		time.Sleep(delay)
 		// time.Sleep(5 * time.Second)
 
-		data, err := os.ReadFile(g.filePath)
+		files, err := provider.ReadFixtures(g.fixtureDir, query)
 		if err != nil {
 			resChan <- result{nil, fmt.Errorf("Lionair.getFlight: Failed to read file")}
 			return
 		}
 
-		var response LionResponse
-		if err := json.Unmarshal(data, &response); err != nil {
-			resChan <- result{nil, err}
-			return
+		var rawFlights []entity.LionFlight
+		for _, data := range files {
+			var response LionResponse
+			if err := json.Unmarshal(data, &response); err != nil {
+				resChan <- result{nil, err}
+				return
+			}
+			rawFlights = append(rawFlights, response.Data.AvailableFlights...)
 		}
 
-		flights, err := g.mapFlights(response.Data.AvailableFlights)
+		flights, err := g.mapFlights(ctx, rawFlights)
 		resChan <- result{flights, err}
 	}()
 
@@ -72,12 +106,11 @@ func (g *lionAirService) GetFlight(ctx context.Context) ([]entity.Flight, error)
 	case res := <-resChan:
 		return res.flights, res.err
 	case <-ctx.Done():
-		return nil, fmt.Errorf("LionAir fetch timed out after 2s")
+		return nil, context.Cause(ctx)
 	}
 }
 
-func (s *lionAirService) mapFlights(rawFlights []entity.LionFlight) ([]entity.Flight, error) {
-	log := logger.Init()
+func (s *lionAirService) mapFlights(ctx context.Context, rawFlights []entity.LionFlight) ([]entity.Flight, error) {
 	if len(rawFlights) == 0 {
 		return []entity.Flight{}, nil
 	}
@@ -85,13 +118,13 @@ func (s *lionAirService) mapFlights(rawFlights []entity.LionFlight) ([]entity.Fl
 	unifiedFlights := make([]entity.Flight, 0, len(rawFlights))
 	for _, raw := range rawFlights {
 		if err := raw.Validate(); err != nil {
-			log.Errorf("LionAir Integrity Error: %v", err)
+			s.logger.WarnContext(ctx, "dropping flight that fails validation", "flight", raw.ID, "error", err)
 			continue
 		}
 
 		unified, err := s.mapFlight(raw)
 		if err != nil {
-			log.Errorf("Error Lion.mapFlights: Corrupted data")
+			s.logger.WarnContext(ctx, "dropping flight that can not be mapped", "flight", raw.ID, "error", err)
 			continue
 		}
 		unifiedFlights = append(unifiedFlights, unified)
@@ -101,15 +134,23 @@ func (s *lionAirService) mapFlights(rawFlights []entity.LionFlight) ([]entity.Fl
 }
 
 func (s *lionAirService) mapFlight(flight entity.LionFlight) (entity.Flight, error) {
+	locationRegistery := entity.NewLocationRegistry(s.airports)
+
 	// Departure
-	locDep, _ := time.LoadLocation(flight.Schedule.DepartureTimezone)
+	locDep, err := s.location(locationRegistery, flight.Schedule.DepartureTimezone, flight.Route.From.Code)
+	if err != nil {
+		return entity.Flight{}, err
+	}
 	depTime, err := time.ParseInLocation("2006-01-02T15:04:05", flight.Schedule.Departure, locDep)
 	if err != nil {
 		return entity.Flight{}, err
 	}
 
 	// Arrival
-	locArr, _ := time.LoadLocation(flight.Schedule.ArrivalTimezone)
+	locArr, err := s.location(locationRegistery, flight.Schedule.ArrivalTimezone, flight.Route.To.Code)
+	if err != nil {
+		return entity.Flight{}, err
+	}
 	arrTime, err := time.ParseInLocation("2006-01-02T15:04:05", flight.Schedule.Arrival, locArr)
 	if err != nil {
 		return entity.Flight{}, err
@@ -132,33 +173,39 @@ func (s *lionAirService) mapFlight(flight entity.LionFlight) (entity.Flight, err
 		amenities = append(amenities, entity.AMENITIES_MEAL)
 	}
 
-	// formated IDR
-	const idr = "IDR"
-	var formattedPrice string
-	if flight.Pricing.Currency == idr {
-		formattedPrice = util.FormatIDR(float64(flight.Pricing.Total))
+	// layovers, Lion Air only sends the airport and the duration
+	layovers := make([]entity.Layover, 0, len(flight.Layovers))
+	for _, stop := range flight.Layovers {
+		layovers = append(layovers, entity.Layover{
+			Airport:         stop.Airport,
+			AirportName:     locationRegistery.GetAirport(stop.Airport),
+			City:            locationRegistery.GetCity(stop.Airport),
+			DurationMinutes: stop.DurationMinutes,
+		})
 	}
 
 	return entity.Flight{
-		ID:       fmt.Sprintf("%s_%s", flight.ID, entity.LIONAIR),
-		Provider: entity.PROVIDER_LION_AIR,
+		ID:       fmt.Sprintf("%s_%s", flight.ID, Code),
+		Provider: Name,
 		Airline: entity.AirlineInfo{
 			Name: flight.Carrier.Name,
 			Code: flight.ID,
 		},
 		FlightNumber: flight.ID,
 		Departure: entity.LocationDetails{
-			Airport:   flight.Route.From.Name,
-			City:      flight.Route.From.City,
+			Airport:   cmp.Or(locationRegistery.GetAirport(flight.Route.From.Code), flight.Route.From.Name),
+			City:      cmp.Or(locationRegistery.GetCity(flight.Route.From.Code), flight.Route.From.City),
 			Datetime:  depTime,
 			Timestamp: depTime.Unix(),
+			Timezone:  locDep.String(),
 			Code:      flight.Route.From.Code,
 		},
 		Arrival: entity.LocationDetails{
-			Airport:   flight.Route.To.Name,
-			City:      flight.Route.To.City,
+			Airport:   cmp.Or(locationRegistery.GetAirport(flight.Route.To.Code), flight.Route.To.Name),
+			City:      cmp.Or(locationRegistery.GetCity(flight.Route.To.Code), flight.Route.To.City),
 			Datetime:  arrTime,
 			Timestamp: arrTime.Unix(),
+			Timezone:  locArr.String(),
 			Code:      flight.Route.To.Code,
 		},
 		Duration: entity.DurationDetails{
@@ -166,13 +213,16 @@ func (s *lionAirService) mapFlight(flight entity.LionFlight) (entity.Flight, err
 			Formatted:    formattedDuration,
 		},
 		Stops:          flight.StopCount,
+		Layovers:       layovers,
 		AvailableSeats: flight.SeatsLeft,
-		CabinClass:     flight.Pricing.FareType,
+		CabinClass:     entity.MapCabinClass(cabinClasses, flight.Pricing.FareType),
+		FareClass:      flight.Pricing.FareType,
 		Aircraft:       &aircraft,
 		Price: entity.PriceDetails{
 			Amount:    flight.Pricing.Total,
 			Currency:  flight.Pricing.Currency,
-			Formatted: formattedPrice,
+			Formatted: util.FormatMoney(flight.Pricing.Total, flight.Pricing.Currency),
+			Breakdown: entity.TotalOnlyFare(),
 		},
 		Baggage: entity.BaggageDetails{
 			CarryOn: flight.Services.BaggageAllowance.Cabin,
@@ -181,3 +231,15 @@ func (s *lionAirService) mapFlight(flight entity.LionFlight) (entity.Flight, err
 		Amenities: amenities,
 	}, nil
 }
+
+// location prefers the airport's zone from the registry, like the other mappers, and
+// only falls back to the timezone Lion Air sends for an airport missing from it
+func (s *lionAirService) location(registry entity.LocationRegistry, timezone, airport string) (*time.Location, error) {
+	if loc := registry.Location(airport); loc != nil {
+		return loc, nil
+	}
+	if loc := entity.LoadLocation(timezone); loc != nil {
+		return loc, nil
+	}
+	return nil, fmt.Errorf("unknown timezone %q for airport %s", timezone, airport)
+}
@@ -0,0 +1,175 @@
+package lionair
+
+import (
+	"flight-aggregator/internal/entity"
+	"flight-aggregator/internal/service/provider"
+	"log/slog"
+	"slices"
+	"testing"
+)
+
+// airports stands in for the airport registry, KOE and UPG are not in it
+type airports map[string]entity.Airport
+
+func (a airports) Get(code string) (entity.Airport, bool) {
+	airport, ok := a[code]
+	return airport, ok
+}
+
+func newTestService() *lionAirService {
+	return NewLionAirService(provider.Options{
+		Airports: airports{
+			"CGK": {Code: "CGK", Name: "Soekarno-Hatta International", City: "Jakarta", Timezone: "Asia/Jakarta"},
+			"DPS": {Code: "DPS", Name: "I Gusti Ngurah Rai International", City: "Denpasar", Timezone: "Asia/Makassar"},
+			"SUB": {Code: "SUB", Name: "Juanda International", City: "Surabaya", Timezone: "Asia/Jakarta"},
+		},
+		Logger: slog.New(slog.DiscardHandler),
+	}).(*lionAirService)
+}
+
+func testFlight() entity.LionFlight {
+	return entity.LionFlight{
+		ID:      "JT740",
+		Carrier: entity.LionCarrier{Name: "Lion Air", IATA: "JT"},
+		Route: entity.LionRoute{
+			From: entity.LionLocation{Code: "CGK", Name: "Soekarno-Hatta", City: "Jakarta"},
+			To:   entity.LionLocation{Code: "DPS", Name: "Ngurah Rai", City: "Denpasar"},
+		},
+		Schedule: entity.LionSchedule{
+			Departure:         "2025-12-15T05:30:00",
+			DepartureTimezone: "Asia/Jakarta",
+			Arrival:           "2025-12-15T08:15:00",
+			ArrivalTimezone:   "Asia/Makassar",
+		},
+		IsDirect:  true,
+		Pricing:   entity.LionPricing{Total: 950000, Currency: "IDR", FareType: "ECONOMY"},
+		SeatsLeft: 45,
+	}
+}
+
+func TestMapFlightCabinClass(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"ECONOMY", entity.CABIN_ECONOMY},
+		{"PROMO", entity.CABIN_ECONOMY},
+		{"economy_promo", entity.CABIN_ECONOMY},
+		{"BUSINESS", entity.CABIN_BUSINESS},
+		{"First", entity.CABIN_FIRST},
+		{"SUPER_SAVER", entity.CABIN_UNKNOWN},
+	}
+
+	s := newTestService()
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			raw := testFlight()
+			raw.Pricing.FareType = tt.raw
+
+			fl, err := s.mapFlight(raw)
+			if err != nil {
+				t.Fatalf("mapFlight: %v", err)
+			}
+			if fl.CabinClass != tt.want || fl.FareClass != tt.raw {
+				t.Errorf("cabin = %q, fare class %q, want %q and the raw %q", fl.CabinClass, fl.FareClass, tt.want, tt.raw)
+			}
+		})
+	}
+}
+
+func TestMapFlightLayovers(t *testing.T) {
+	tests := []struct {
+		name      string
+		stopCount int
+		stops     []entity.LionStop
+		want      []entity.Layover
+	}{
+		{"direct", 0, nil, []entity.Layover{}},
+		{
+			name:      "one stop",
+			stopCount: 1,
+			stops:     []entity.LionStop{{Airport: "SUB", DurationMinutes: 50}},
+			want:      []entity.Layover{{Airport: "SUB", AirportName: "Juanda International", City: "Surabaya", DurationMinutes: 50}},
+		},
+		{
+			name:      "an airport missing from the registry keeps its code",
+			stopCount: 2,
+			stops:     []entity.LionStop{{Airport: "SUB", DurationMinutes: 50}, {Airport: "UPG", DurationMinutes: 80}},
+			want: []entity.Layover{
+				{Airport: "SUB", AirportName: "Juanda International", City: "Surabaya", DurationMinutes: 50},
+				{Airport: "UPG", DurationMinutes: 80},
+			},
+		},
+		{
+			// the service treats the missing layovers as unknown, see matchesLayoverFilters
+			name:      "a stop count without layovers is kept as sent",
+			stopCount: 1,
+			want:      []entity.Layover{},
+		},
+	}
+
+	s := newTestService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := testFlight()
+			raw.StopCount, raw.Layovers = tt.stopCount, tt.stops
+
+			fl, err := s.mapFlight(raw)
+			if err != nil {
+				t.Fatalf("mapFlight: %v", err)
+			}
+			if fl.Stops != tt.stopCount {
+				t.Errorf("stops = %d, want %d", fl.Stops, tt.stopCount)
+			}
+			if !slices.Equal(fl.Layovers, tt.want) {
+				t.Errorf("layovers = %+v, want %+v", fl.Layovers, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapFlightTimezones(t *testing.T) {
+	tests := []struct {
+		name            string
+		to              string
+		arrivalTimezone string
+		wantZone        string
+		wantAirport     string
+		wantMinutes     int
+		wantErr         bool
+	}{
+		{"the registry zone", "DPS", "Asia/Makassar", "Asia/Makassar", "I Gusti Ngurah Rai International", 105, false},
+		{"the registry wins over a wrong zone from Lion Air", "DPS", "Asia/Jakarta", "Asia/Makassar", "I Gusti Ngurah Rai International", 105, false},
+		{"Lion Air's zone for an airport the registry does not know", "KOE", "Asia/Jayapura", "Asia/Jayapura", "Ngurah Rai", 45, false},
+		{"no zone from either", "KOE", "Asia/Atlantis", "", "", 0, true},
+	}
+
+	s := newTestService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := testFlight()
+			raw.Route.To.Code, raw.Schedule.ArrivalTimezone = tt.to, tt.arrivalTimezone
+
+			fl, err := s.mapFlight(raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("mapFlight = %v, want an error", fl.Arrival.Timezone)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("mapFlight: %v", err)
+			}
+			if fl.Arrival.Timezone != tt.wantZone || fl.Arrival.Datetime.Format("15:04") != "08:15" {
+				t.Errorf("arrival = %s in %s, want 08:15 in %s", fl.Arrival.Datetime.Format("15:04"), fl.Arrival.Timezone, tt.wantZone)
+			}
+			// the registry names the airport, Lion Air's name is the fallback
+			if fl.Arrival.Airport != tt.wantAirport {
+				t.Errorf("arrival airport = %q, want %q", fl.Arrival.Airport, tt.wantAirport)
+			}
+			if fl.Duration.TotalMinutes != tt.wantMinutes {
+				t.Errorf("duration = %d minutes, want %d", fl.Duration.TotalMinutes, tt.wantMinutes)
+			}
+		})
+	}
+}
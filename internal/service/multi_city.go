@@ -0,0 +1,234 @@
+package service
+
+import (
+	"container/heap"
+	"context"
+	"flight-aggregator/internal/entity"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// used when the request does not set minConnectionMinutes
+	defaultMinConnectionMinutes = 60
+	// cheapest and best value flights kept per leg when combining legs
+	maxLegCandidates = 8
+	maxItineraries   = 20
+)
+
+func (f *flightService) SearchMultiCity(ctx context.Context, req entity.MultiCitySearchRequest) (entity.MultiCitySearchResponse, error) {
+	startTime := time.Now()
+
+	if err := req.Validate(); err != nil {
+		return entity.MultiCitySearchResponse{}, fmt.Errorf("%w: %w", entity.ErrInvalidRequest, err)
+	}
+	if err := f.checkDisplayCurrency(req.DisplayCurrency); err != nil {
+		return entity.MultiCitySearchResponse{}, err
+	}
+
+	ctx, cancel := f.withSearchDeadline(ctx, req.MaxWaitMs)
+	defer cancel()
+
+	minConnection := req.MinConnectionMinutes
+	if minConnection == 0 {
+		minConnection = defaultMinConnectionMinutes
+	}
+
+	// every leg is a normal one-way search, so each benefits from the per-airline cache
+	legRequests := make([]entity.SearchRequest, len(req.Legs))
+	results := make([]legResult, len(req.Legs))
+	var wg sync.WaitGroup
+	for i := range req.Legs {
+		legRequests[i] = req.LegRequest(i)
+		f.standardizeRequest(&legRequests[i])
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = f.searchLeg(ctx, legRequests[i])
+		}(i)
+	}
+	wg.Wait()
+
+	response := entity.MultiCitySearchResponse{
+		Legs: make([]entity.LegResult, len(results)),
+	}
+	candidates := make([][]entity.Flight, len(results))
+	for i, res := range results {
+		legReq := legRequests[i]
+		response.Legs[i] = entity.LegResult{
+			SearchCriteria: newSearchCriteria(legReq),
+			BestValue:      res.bestValue,
+			Flights:        res.flights,
+		}
+		candidates[i] = f.legCandidates(res.flights)
+	}
+
+	response.Itineraries, response.BestValueItinerary = f.assembleItineraries(candidates, time.Duration(minConnection)*time.Minute)
+	response.Metadata = f.summarizeLegs(results...)
+	response.Metadata.SearchTimeMs = time.Since(startTime).Milliseconds()
+
+	return response, nil
+}
+
+// legCandidates keeps the cheapest and the best value flights of a leg so the
+// number of combinations stays bounded no matter how many flights a leg has
+func (f *flightService) legCandidates(flights []entity.Flight) []entity.Flight {
+	if len(flights) <= maxLegCandidates {
+		return flights
+	}
+
+	byPrice := append([]entity.Flight(nil), flights...)
+	sort.SliceStable(byPrice, func(i, j int) bool {
+		return byPrice[i].Price.TotalAmount < byPrice[j].Price.TotalAmount
+	})
+	byScore := append([]entity.Flight(nil), flights...)
+	sort.SliceStable(byScore, func(i, j int) bool {
+		return f.bestValueScore(byScore[i]) < f.bestValueScore(byScore[j])
+	})
+
+	seen := make(map[string]bool, maxLegCandidates*2)
+	candidates := make([]entity.Flight, 0, maxLegCandidates*2)
+	for _, list := range [][]entity.Flight{byPrice[:maxLegCandidates], byScore[:maxLegCandidates]} {
+		for _, fl := range list {
+			if seen[fl.ID] {
+				continue
+			}
+			seen[fl.ID] = true
+			candidates = append(candidates, fl)
+		}
+	}
+	return candidates
+}
+
+// assembleItineraries walks the combinations of one flight per leg where each leg
+// departs at least minConnection after the previous one lands. It returns the
+// cheapest itineraries and the best value one. Only the kept combinations are
+// built, and a branch is cut as soon as it can no longer beat either of them.
+func (f *flightService) assembleItineraries(legs [][]entity.Flight, minConnection time.Duration) ([]entity.Itinerary, *entity.Itinerary) {
+	if len(legs) == 0 {
+		return []entity.Itinerary{}, nil
+	}
+
+	// the lowest price and score the legs from i on can still add, the bound used to cut a branch
+	scores := make([][]float64, len(legs))
+	minPrice := make([]float64, len(legs)+1)
+	minScore := make([]float64, len(legs)+1)
+	for i := len(legs) - 1; i >= 0; i-- {
+		if len(legs[i]) == 0 {
+			return []entity.Itinerary{}, nil
+		}
+		scores[i] = make([]float64, len(legs[i]))
+		legPrice, legScore := math.MaxFloat64, math.MaxFloat64
+		for j, fl := range legs[i] {
+			scores[i][j] = f.bestValueScore(fl)
+			legPrice = min(legPrice, fl.Price.TotalAmount)
+			legScore = min(legScore, scores[i][j])
+		}
+		minPrice[i] = minPrice[i+1] + legPrice
+		minScore[i] = minScore[i+1] + legScore
+	}
+
+	cheapest := &combinationHeap{}
+	var best *combination
+	found := 0
+
+	cheaperPossible := func(price float64) bool {
+		return cheapest.Len() < maxItineraries || price < (*cheapest)[0].price
+	}
+	betterPossible := func(score float64) bool {
+		return best == nil || score < best.score
+	}
+
+	path := make([]entity.Flight, 0, len(legs))
+	var walk func(leg int, price, score float64)
+	walk = func(leg int, price, score float64) {
+		if leg == len(legs) {
+			keepCheapest, keepBest := cheaperPossible(price), betterPossible(score)
+			found++
+			if !keepCheapest && !keepBest {
+				return
+			}
+			c := combination{flights: slices.Clone(path), price: price, score: score, seq: found}
+			if keepCheapest {
+				heap.Push(cheapest, c)
+				if cheapest.Len() > maxItineraries {
+					heap.Pop(cheapest)
+				}
+			}
+			if keepBest {
+				best = &c
+			}
+			return
+		}
+
+		for i, fl := range legs[leg] {
+			if leg > 0 {
+				previous := path[leg-1]
+				if fl.Departure.Datetime.Sub(previous.Arrival.Datetime) < minConnection {
+					continue
+				}
+			}
+			nextPrice, nextScore := price+fl.Price.TotalAmount, score+scores[leg][i]
+			if !cheaperPossible(nextPrice+minPrice[leg+1]) && !betterPossible(nextScore+minScore[leg+1]) {
+				continue
+			}
+			path = append(path, fl)
+			walk(leg+1, nextPrice, nextScore)
+			path = path[:len(path)-1]
+		}
+	}
+	walk(0, 0, 0)
+
+	if best == nil {
+		return []entity.Itinerary{}, nil
+	}
+
+	kept := []combination(*cheapest)
+	sort.Slice(kept, func(i, j int) bool {
+		if kept[i].price != kept[j].price {
+			return kept[i].price < kept[j].price
+		}
+		return kept[i].seq < kept[j].seq
+	})
+
+	itineraries := make([]entity.Itinerary, len(kept))
+	for i, c := range kept {
+		itineraries[i] = buildItinerary(c.flights...)
+	}
+	bestValue := buildItinerary(best.flights...)
+	return itineraries, &bestValue
+}
+
+// combination is one flight per leg with its total price and best value score
+type combination struct {
+	flights []entity.Flight
+	price   float64
+	score   float64
+	// walk order, equal prices keep the order they were found in
+	seq int
+}
+
+// combinationHeap keeps the cheapest combinations with the most expensive on top,
+// so it is the one dropped when a cheaper one is found
+type combinationHeap []combination
+
+func (h combinationHeap) Len() int { return len(h) }
+func (h combinationHeap) Less(i, j int) bool {
+	if h[i].price != h[j].price {
+		return h[i].price > h[j].price
+	}
+	return h[i].seq > h[j].seq
+}
+func (h combinationHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *combinationHeap) Push(x any)   { *h = append(*h, x.(combination)) }
+func (h *combinationHeap) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
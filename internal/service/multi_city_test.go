@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"flight-aggregator/internal/entity"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+)
+
+// randomLegs builds legs of flights spread over consecutive days, with prices and
+// durations drawn so that ties and missed connections both happen
+func randomLegs(f *flightService, seed uint64, legs, perLeg int) [][]entity.Flight {
+	r := rand.New(rand.NewPCG(seed, 0))
+	airports := []string{"CGK", "DPS", "SUB", "UPG", "KNO", "BPN", "JOG"}
+
+	result := make([][]entity.Flight, legs)
+	for leg := range result {
+		day := time.Date(2025, 12, 15+leg, 0, 0, 0, 0, time.UTC)
+		for i := 0; i < perLeg; i++ {
+			// from the evening before to the evening of the day, so some legs leave before the previous one lands
+			dep := day.Add(time.Duration(r.IntN(30)-6) * time.Hour)
+			fl := legFlight(fmt.Sprintf("L%d-%d", leg, i), "Garuda Indonesia", airports[leg], airports[leg+1], dep, 60+r.IntN(6)*30, float64(5+r.IntN(10))*100000)
+			fl.Stops = r.IntN(2)
+			result[leg] = append(result[leg], fl)
+		}
+		f.applyPassengerPricing(result[leg], entity.SearchRequest{Adults: 1})
+	}
+	return result
+}
+
+// allItineraries walks every combination in the order assembleItineraries does,
+// with no bound, and keeps the same ones: the cheapest by price then walk order,
+// and the first combination with the lowest score
+func allItineraries(f *flightService, legs [][]entity.Flight, minConnection time.Duration) ([][]string, []string) {
+	type found struct {
+		ids          []string
+		price, score float64
+	}
+	var combos []found
+
+	var walk func(path []entity.Flight)
+	walk = func(path []entity.Flight) {
+		leg := len(path)
+		if leg == len(legs) {
+			c := found{ids: flightIDs(path)}
+			for _, fl := range path {
+				c.price += fl.Price.TotalAmount
+				c.score += f.bestValueScore(fl)
+			}
+			combos = append(combos, c)
+			return
+		}
+		for _, fl := range legs[leg] {
+			if leg > 0 && fl.Departure.Datetime.Sub(path[leg-1].Arrival.Datetime) < minConnection {
+				continue
+			}
+			walk(append(slices.Clone(path), fl))
+		}
+	}
+	walk(nil)
+
+	var best []string
+	bestScore := 0.0
+	for _, c := range combos {
+		if best == nil || c.score < bestScore {
+			best, bestScore = c.ids, c.score
+		}
+	}
+
+	sort.SliceStable(combos, func(i, j int) bool { return combos[i].price < combos[j].price })
+	cheapest := make([][]string, 0, maxItineraries)
+	for i := 0; i < len(combos) && i < maxItineraries; i++ {
+		cheapest = append(cheapest, combos[i].ids)
+	}
+	return cheapest, best
+}
+
+func TestAssembleItinerariesMatchesEveryCombination(t *testing.T) {
+	tests := []struct {
+		legs, perLeg  int
+		minConnection time.Duration
+	}{
+		{2, 3, time.Hour},
+		{2, 8, time.Hour},
+		{3, 6, 0},
+		{3, 6, 2 * time.Hour},
+		{4, 5, time.Hour},
+		{6, 4, 90 * time.Minute},
+	}
+
+	f := newTestService(t, newMemCache())
+	for _, tt := range tests {
+		for seed := uint64(1); seed <= 20; seed++ {
+			t.Run(fmt.Sprintf("%d legs of %d, %s, seed %d", tt.legs, tt.perLeg, tt.minConnection, seed), func(t *testing.T) {
+				legs := randomLegs(f, seed, tt.legs, tt.perLeg)
+				wantCheapest, wantBest := allItineraries(f, legs, tt.minConnection)
+
+				itineraries, best := f.assembleItineraries(legs, tt.minConnection)
+				cheapest := make([][]string, len(itineraries))
+				for i := range itineraries {
+					cheapest[i] = flightIDs(itineraries[i].Legs)
+				}
+				if !slices.EqualFunc(cheapest, wantCheapest, slices.Equal) {
+					t.Errorf("cheapest = %v, want %v", cheapest, wantCheapest)
+				}
+				if got := itineraryIDs(best); !slices.Equal(got, wantBest) {
+					t.Errorf("best value = %v, want %v", got, wantBest)
+				}
+			})
+		}
+	}
+}
+
+func TestAssembleItinerariesMinConnection(t *testing.T) {
+	lands := time.Date(2025, 12, 15, 8, 0, 0, 0, time.UTC)
+	first := legFlight("CGK-DPS", "Garuda Indonesia", "CGK", "DPS", lands.Add(-2*time.Hour), 120, 1000000)
+
+	tests := []struct {
+		name          string
+		gap           time.Duration
+		minConnection time.Duration
+		want          bool
+	}{
+		{"long enough", 3 * time.Hour, time.Hour, true},
+		{"exactly the minimum", time.Hour, time.Hour, true},
+		{"a minute short", 59 * time.Minute, time.Hour, false},
+		{"leaves before landing", -time.Hour, 0, false},
+		{"no minimum, right after landing", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTestService(t, newMemCache())
+			legs := [][]entity.Flight{
+				{first},
+				{legFlight("DPS-SUB", "Garuda Indonesia", "DPS", "SUB", lands.Add(tt.gap), 60, 800000)},
+			}
+			f.applyPassengerPricing(legs[0], entity.SearchRequest{Adults: 1})
+			f.applyPassengerPricing(legs[1], entity.SearchRequest{Adults: 1})
+
+			itineraries, best := f.assembleItineraries(legs, tt.minConnection)
+			if got := len(itineraries) == 1 && best != nil; got != tt.want {
+				t.Fatalf("connects = %v (%d itineraries), want %v", got, len(itineraries), tt.want)
+			}
+			if tt.want && itineraries[0].TotalPrice.TotalAmount != 1800000 {
+				t.Errorf("total = %v, want 1800000", itineraries[0].TotalPrice.TotalAmount)
+			}
+		})
+	}
+}
+
+func TestSearchMultiCity(t *testing.T) {
+	at := func(day, hour int) time.Time { return time.Date(2025, 12, day, hour, 0, 0, 0, time.UTC) }
+	garuda := &fakeProvider{code: "Garuda", name: "Garuda Indonesia", flights: map[string][]entity.Flight{
+		routeKey("CGK", "DPS", "2025-12-15"): {
+			legFlight("GA1", "Garuda Indonesia", "CGK", "DPS", at(15, 6), 120, 1000000),
+			legFlight("GA2", "Garuda Indonesia", "CGK", "DPS", at(15, 14), 120, 700000),
+		},
+		routeKey("DPS", "SUB", "2025-12-15"): {
+			// 90 minutes after GA1 lands, before GA2 does
+			legFlight("GA3", "Garuda Indonesia", "DPS", "SUB", at(15, 9).Add(30*time.Minute), 60, 500000),
+			legFlight("GA4", "Garuda Indonesia", "DPS", "SUB", at(15, 20), 60, 900000),
+		},
+	}}
+
+	tests := []struct {
+		name          string
+		minConnection int
+		want          [][]string
+	}{
+		{"default hour", 0, [][]string{{"GA1", "GA3"}, {"GA2", "GA4"}, {"GA1", "GA4"}}},
+		{"two hours between legs", 120, [][]string{{"GA2", "GA4"}, {"GA1", "GA4"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTestService(t, newMemCache(), garuda)
+			res, err := f.SearchMultiCity(context.Background(), entity.MultiCitySearchRequest{
+				Legs: []entity.SearchLeg{
+					{Origin: "CGK", Destination: "DPS", DepartureDate: "2025-12-15"},
+					{Origin: "DPS", Destination: "SUB", DepartureDate: "2025-12-15"},
+				},
+				MinConnectionMinutes: tt.minConnection,
+			})
+			if err != nil {
+				t.Fatalf("SearchMultiCity: %v", err)
+			}
+
+			got := make([][]string, len(res.Itineraries))
+			for i := range res.Itineraries {
+				got[i] = flightIDs(res.Itineraries[i].Legs)
+			}
+			if !slices.EqualFunc(got, tt.want, slices.Equal) {
+				t.Errorf("itineraries = %v, want %v", got, tt.want)
+			}
+			if len(res.Legs) != 2 || len(res.Legs[0].Flights) != 2 || len(res.Legs[1].Flights) != 2 {
+				t.Errorf("legs = %d, want 2 legs of 2 flights", len(res.Legs))
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"flight-aggregator/internal/common/util"
+	"flight-aggregator/internal/entity"
+	"fmt"
+	"strings"
+)
+
+// scoreCurrency is the currency the best value weights are expressed in
+const scoreCurrency = "IDR"
+
+// displayCurrency is the requested currency, or the configured default
+func (f *flightService) displayCurrency(requested string) string {
+	if requested == "" {
+		return f.config.DisplayCurrency
+	}
+	return strings.ToUpper(requested)
+}
+
+func (f *flightService) checkDisplayCurrency(requested string) error {
+	currency := f.displayCurrency(requested)
+	if !f.rates.Supports(currency) {
+		return fmt.Errorf("%w: no exchange rate for displayCurrency %s", entity.ErrInvalidRequest, currency)
+	}
+	return nil
+}
+
+// convertPrices brings every flight to currency and keeps the provider quote in
+// Price.Original. A flight in a currency without a rate can not be compared with
+// the others and is dropped. flights must not be shared, it is converted in place.
+func (f *flightService) convertPrices(ctx context.Context, flights []entity.Flight, currency string) []entity.Flight {
+	kept := flights[:0]
+	for _, fl := range flights {
+		price := &fl.Price
+		if price.Currency != currency {
+			rate, err := f.rates.Rate(price.Currency, currency)
+			if err != nil {
+				f.logger.WarnContext(ctx, "dropping flight, can not convert its price", "flight", fl.ID, "error", err)
+				continue
+			}
+
+			price.Original = &entity.OriginalPrice{
+				Amount:    price.Amount,
+				Currency:  price.Currency,
+				Formatted: util.FormatMoney(price.Amount, price.Currency),
+			}
+			price.Amount = util.RoundMoney(price.Amount*rate, currency)
+			price.Currency = currency
+			price.Formatted = util.FormatMoney(price.Amount, currency)
+			price.Breakdown = convertBreakdown(price.Breakdown, rate, price.Amount, currency)
+		}
+		kept = append(kept, fl)
+	}
+	return kept
+}
+
+// convertBreakdown returns a converted copy, the original is shared with the cache.
+// Surcharges take the rounding difference so the components still add up to amount.
+func convertBreakdown(breakdown *entity.FareBreakdown, rate, amount float64, currency string) *entity.FareBreakdown {
+	if breakdown == nil || breakdown.Source != entity.FARE_ITEMIZED {
+		return breakdown
+	}
+
+	base := util.RoundMoney(breakdown.BaseFare*rate, currency)
+	taxes := util.RoundMoney(breakdown.Taxes*rate, currency)
+	return &entity.FareBreakdown{
+		Source:     entity.FARE_ITEMIZED,
+		BaseFare:   base,
+		Taxes:      taxes,
+		Surcharges: util.RoundMoney(amount-base-taxes, currency),
+	}
+}
+
+// applyPassengerPricing prices the whole party on every flight. Price.Amount stays
+// the adult fare the provider quoted, children and infants are derived from it
+// with the fare rule of the flight's provider.
+func (f *flightService) applyPassengerPricing(flights []entity.Flight, req entity.SearchRequest) {
+	codes := f.providerCodesByName()
+
+	for i := range flights {
+		price := &flights[i].Price
+		rule := f.config.Fares.RuleFor(codes[flights[i].Provider])
+
+		price.PassengerFares = nil
+		price.TotalAmount = 0
+		for _, pax := range []struct {
+			kind     string
+			count    int
+			fraction float64
+		}{
+			{entity.PAX_ADULT, req.Adults, 1},
+			{entity.PAX_CHILD, req.Children, rule.Child},
+			{entity.PAX_INFANT, req.Infants, rule.Infant},
+		} {
+			if pax.count == 0 {
+				continue
+			}
+			fare := newPassengerFare(pax.kind, pax.count, util.RoundMoney(price.Amount*pax.fraction, price.Currency), price.Currency)
+			price.PassengerFares = append(price.PassengerFares, fare)
+			price.TotalAmount += fare.TotalAmount
+		}
+
+		price.TotalAmount = util.RoundMoney(price.TotalAmount, price.Currency)
+		price.Passengers = req.Adults + req.Children + req.Infants
+		price.TotalFormatted = util.FormatMoney(price.TotalAmount, price.Currency)
+	}
+}
+
+// providerCodesByName maps the provider name a flight carries back to its code
+func (f *flightService) providerCodesByName() map[string]string {
+	codes := make(map[string]string)
+	for _, code := range f.providers.Codes() {
+		if p, ok := f.providers.Get(code); ok {
+			codes[p.Name()] = code
+		}
+	}
+	return codes
+}
+
+func newPassengerFare(kind string, count int, amount float64, currency string) entity.PassengerFare {
+	total := util.RoundMoney(amount*float64(count), currency)
+	return entity.PassengerFare{
+		Type:           kind,
+		Count:          count,
+		Amount:         amount,
+		Formatted:      util.FormatMoney(amount, currency),
+		TotalAmount:    total,
+		TotalFormatted: util.FormatMoney(total, currency),
+	}
+}
+
+// mergePassengerFares adds up the fares of several legs per passenger type
+func mergePassengerFares(legs []entity.Flight) []entity.PassengerFare {
+	var merged []entity.PassengerFare
+	index := make(map[string]int)
+	currency := legs[0].Price.Currency
+
+	for _, leg := range legs {
+		for _, fare := range leg.Price.PassengerFares {
+			i, ok := index[fare.Type]
+			if !ok {
+				index[fare.Type] = len(merged)
+				merged = append(merged, newPassengerFare(fare.Type, fare.Count, fare.Amount, currency))
+				continue
+			}
+			merged[i] = newPassengerFare(fare.Type, fare.Count, util.RoundMoney(merged[i].Amount+fare.Amount, currency), currency)
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"flight-aggregator/internal/entity"
+	"slices"
+	"testing"
+)
+
+func TestApplyPassengerPricing(t *testing.T) {
+	garuda := &fakeProvider{code: "Garuda", name: "Garuda Indonesia"}
+	lion := &fakeProvider{code: "LionAir", name: "Lion Air"}
+
+	// fare is the type, count, amount a head and total of one passenger fare
+	type fare struct {
+		kind          string
+		count         int
+		amount, total float64
+	}
+
+	tests := []struct {
+		name                      string
+		provider                  string
+		amount                    float64
+		currency                  string
+		adults, children, infants int
+		wantTotal                 float64
+		wantFormatted             string
+		wantFares                 []fare
+	}{
+		{
+			name: "one adult pays the quoted fare", provider: "Garuda Indonesia", amount: 1000000, currency: "IDR",
+			adults: 1, wantTotal: 1000000, wantFormatted: "Rp 1.000.000",
+			wantFares: []fare{{entity.PAX_ADULT, 1, 1000000, 1000000}},
+		},
+		{
+			name: "the default rule discounts children and infants", provider: "Garuda Indonesia", amount: 1000000, currency: "IDR",
+			adults: 2, children: 1, infants: 1, wantTotal: 2850000, wantFormatted: "Rp 2.850.000",
+			wantFares: []fare{
+				{entity.PAX_ADULT, 2, 1000000, 2000000},
+				{entity.PAX_CHILD, 1, 750000, 750000},
+				{entity.PAX_INFANT, 1, 100000, 100000},
+			},
+		},
+		{
+			name: "a provider rule overrides the default", provider: "Lion Air", amount: 1000000, currency: "IDR",
+			adults: 2, children: 1, infants: 1, wantTotal: 3100000, wantFormatted: "Rp 3.100.000",
+			wantFares: []fare{
+				{entity.PAX_ADULT, 2, 1000000, 2000000},
+				{entity.PAX_CHILD, 1, 1000000, 1000000},
+				{entity.PAX_INFANT, 1, 100000, 100000},
+			},
+		},
+		{
+			name: "an unknown provider gets the default rule", provider: "Someone Else", amount: 1000000, currency: "IDR",
+			adults: 1, children: 1, wantTotal: 1750000, wantFormatted: "Rp 1.750.000",
+			wantFares: []fare{
+				{entity.PAX_ADULT, 1, 1000000, 1000000},
+				{entity.PAX_CHILD, 1, 750000, 750000},
+			},
+		},
+		{
+			name: "fares are rounded to the currency", provider: "Garuda Indonesia", amount: 75.61, currency: "USD",
+			adults: 1, children: 2, infants: 1, wantTotal: 196.59, wantFormatted: "$196.59",
+			wantFares: []fare{
+				{entity.PAX_ADULT, 1, 75.61, 75.61},
+				{entity.PAX_CHILD, 2, 56.71, 113.42},
+				{entity.PAX_INFANT, 1, 7.56, 7.56},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTestService(t, newMemCache(), garuda, lion)
+			flights := []entity.Flight{testFlight("FL", tt.provider, tt.amount)}
+			flights[0].Price.Currency = tt.currency
+
+			f.applyPassengerPricing(flights, entity.SearchRequest{Adults: tt.adults, Children: tt.children, Infants: tt.infants})
+
+			price := flights[0].Price
+			if price.Amount != tt.amount {
+				t.Errorf("amount = %v, want the adult fare %v", price.Amount, tt.amount)
+			}
+			if price.TotalAmount != tt.wantTotal || price.TotalFormatted != tt.wantFormatted {
+				t.Errorf("total = %v %q, want %v %q", price.TotalAmount, price.TotalFormatted, tt.wantTotal, tt.wantFormatted)
+			}
+			if want := tt.adults + tt.children + tt.infants; price.Passengers != want {
+				t.Errorf("passengers = %d, want %d", price.Passengers, want)
+			}
+			var got []fare
+			for _, pf := range price.PassengerFares {
+				got = append(got, fare{pf.Type, pf.Count, pf.Amount, pf.TotalAmount})
+			}
+			if !slices.Equal(got, tt.wantFares) {
+				t.Errorf("passenger fares = %v, want %v", got, tt.wantFares)
+			}
+		})
+	}
+}
+
+func TestMergePassengerFares(t *testing.T) {
+	f := newTestService(t, newMemCache(), &fakeProvider{code: "LionAir", name: "Lion Air"})
+	// Lion Air charges the child the adult fare, Garuda three quarters of it
+	legs := []entity.Flight{
+		testFlight("JT1", "Lion Air", 800000),
+		testFlight("GA1", "Garuda Indonesia", 1000000),
+	}
+	f.applyPassengerPricing(legs, entity.SearchRequest{Adults: 2, Children: 1})
+
+	merged := mergePassengerFares(legs)
+	want := []entity.PassengerFare{
+		newPassengerFare(entity.PAX_ADULT, 2, 1800000, "IDR"),
+		newPassengerFare(entity.PAX_CHILD, 1, 1550000, "IDR"),
+	}
+	if !slices.Equal(merged, want) {
+		t.Fatalf("merged = %+v, want %+v", merged, want)
+	}
+}
+
+func TestConvertBreakdown(t *testing.T) {
+	tests := []struct {
+		name      string
+		breakdown *entity.FareBreakdown
+		rate      float64
+		amount    float64
+		currency  string
+		want      *entity.FareBreakdown
+	}{
+		{"no breakdown", nil, 1, 0, "USD", nil},
+		{"a total stays a total", entity.TotalOnlyFare(), 1.0 / 16000, 75, "USD", entity.TotalOnlyFare()},
+		{
+			name:      "every component is converted",
+			breakdown: entity.ItemizedFare(1040000, 160000, 1200000),
+			rate:      1.0 / 16000, amount: 75, currency: "USD",
+			want: &entity.FareBreakdown{Source: entity.FARE_ITEMIZED, BaseFare: 65, Taxes: 10},
+		},
+		{
+			// base and taxes are RM 33.33 each of RM 66.67, the cent both lost goes to surcharges
+			name:      "surcharges take the rounding",
+			breakdown: entity.ItemizedFare(100000, 100000, 200000),
+			rate:      1.0 / 3000, amount: 66.67, currency: "MYR",
+			want: &entity.FareBreakdown{Source: entity.FARE_ITEMIZED, BaseFare: 33.33, Taxes: 33.33, Surcharges: 0.01},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := convertBreakdown(tt.breakdown, tt.rate, tt.amount, tt.currency)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Fatalf("convertBreakdown = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestItineraryBreakdown(t *testing.T) {
+	itemized := testFlight("ID1", "Batik Air", 1200000)
+	itemized.Price.Breakdown = entity.ItemizedFare(1000000, 150000, 1200000)
+	another := testFlight("ID2", "Batik Air", 600000)
+	another.Price.Breakdown = entity.ItemizedFare(500000, 100000, 600000)
+	total := testFlight("GA1", "Garuda Indonesia", 1000000)
+	total.Price.Breakdown = entity.TotalOnlyFare()
+
+	tests := []struct {
+		name string
+		legs []entity.Flight
+		want entity.FareBreakdown
+	}{
+		{"itemized legs add up", []entity.Flight{itemized, another}, entity.FareBreakdown{Source: entity.FARE_ITEMIZED, BaseFare: 1500000, Taxes: 250000, Surcharges: 50000}},
+		{"one total_only leg makes it total_only", []entity.Flight{itemized, total}, entity.FareBreakdown{Source: entity.FARE_TOTAL_ONLY}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			itinerary := buildItinerary(tt.legs...)
+			if got := *itinerary.TotalPrice.Breakdown; got != tt.want {
+				t.Fatalf("breakdown = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertPrices(t *testing.T) {
+	f := newTestService(t, newMemCache())
+
+	rupiah := testFlight("GA1", "Garuda Indonesia", 1600000)
+	dollars := testFlight("SQ1", "Singapore Airlines", 100)
+	dollars.Price.Currency, dollars.Price.Formatted = "SGD", "S$100.00"
+	won := testFlight("KE1", "Korean Air", 100000)
+	won.Price.Currency, won.Price.Formatted = "KRW", "KRW 100,000.00"
+
+	tests := []struct {
+		name          string
+		currency      string
+		wantIDs       []string
+		wantAmounts   []float64
+		wantFormatted []string
+		// wantOriginal is the formatted provider quote, "" when the price was not converted
+		wantOriginal []string
+	}{
+		{"to rupiah", "IDR", []string{"GA1", "SQ1"}, []float64{1600000, 1200000},
+			[]string{"Rp 1.600.000", "Rp 1.200.000"}, []string{"", "S$100.00"}},
+		{"to dollars", "USD", []string{"GA1", "SQ1"}, []float64{100, 75},
+			[]string{"$100.00", "$75.00"}, []string{"Rp 1.600.000", "S$100.00"}},
+		{"to the provider's currency", "SGD", []string{"GA1", "SQ1"}, []float64{133.33, 100},
+			[]string{"S$133.33", "S$100.00"}, []string{"Rp 1.600.000", ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flights := f.convertPrices(context.Background(), []entity.Flight{rupiah, dollars, won}, tt.currency)
+
+			if got := flightIDs(flights); !slices.Equal(got, tt.wantIDs) {
+				t.Fatalf("flights = %v, want %v, a price without a rate is dropped", got, tt.wantIDs)
+			}
+			for i, fl := range flights {
+				original := ""
+				if fl.Price.Original != nil {
+					original = fl.Price.Original.Formatted
+				}
+				if fl.Price.Currency != tt.currency || fl.Price.Amount != tt.wantAmounts[i] ||
+					fl.Price.Formatted != tt.wantFormatted[i] || original != tt.wantOriginal[i] {
+					t.Errorf("%s = %v %s %q from %q, want %v %s %q from %q", fl.ID,
+						fl.Price.Amount, fl.Price.Currency, fl.Price.Formatted, original,
+						tt.wantAmounts[i], tt.currency, tt.wantFormatted[i], tt.wantOriginal[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"flight-aggregator/internal/entity"
+	"log/slog"
+	"time"
+)
+
+// DefaultTimeout bounds a single GetFlight call when Options.Timeout is not set
+const DefaultTimeout = 2 * time.Second
+
+// Options is what every mock provider is built from
+type Options struct {
+	// FixtureDir holds one mock response per route and date
+	FixtureDir string
+	// Timeout bounds a single GetFlight call
+	Timeout time.Duration
+	// Airports names the airports and gives their timezones
+	Airports entity.AirportLookup
+	// Logger reports the flights a provider drops while mapping its response
+	Logger *slog.Logger
+}
+
+// TimeoutOrDefault is Timeout, or DefaultTimeout when it is not set
+func (o Options) TimeoutOrDefault() time.Duration {
+	if o.Timeout > 0 {
+		return o.Timeout
+	}
+	return DefaultTimeout
+}
+
+// LoggerOrDefault is Logger, or slog's default logger when it is not set
+func (o Options) LoggerOrDefault() *slog.Logger {
+	if o.Logger != nil {
+		return o.Logger
+	}
+	return slog.Default()
+}
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+	"flight-aggregator/internal/entity"
+	"fmt"
+	"sync"
+)
+
+// Provider is a single airline source the aggregator can search
+type Provider interface {
+	// Code is the stable key used in requests, cache keys and flight IDs (e.g. "Garuda")
+	Code() string
+	// Name is the display name shown in the unified flight (e.g. "Garuda Indonesia")
+	Name() string
+	GetFlight(ctx context.Context, query Query) ([]entity.Flight, error)
+}
+
+type registry struct {
+	mu        sync.RWMutex
+	providers map[string]Provider
+	codes     []string
+}
+
+type Registry interface {
+	Register(p Provider) error
+	Get(code string) (Provider, bool)
+	// Codes returns every registered code in registration order
+	Codes() []string
+}
+
+func NewRegistry(providers ...Provider) (Registry, error) {
+	r := &registry{
+		providers: make(map[string]Provider, len(providers)),
+	}
+
+	for _, p := range providers {
+		if err := r.Register(p); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+func (r *registry) Register(p Provider) error {
+	if p == nil || p.Code() == "" {
+		return fmt.Errorf("provider.Register: provider code is required")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.providers[p.Code()]; exists {
+		return fmt.Errorf("provider.Register: %s already registered", p.Code())
+	}
+
+	r.providers[p.Code()] = p
+	r.codes = append(r.codes, p.Code())
+	return nil
+}
+
+func (r *registry) Get(code string) (Provider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.providers[code]
+	return p, ok
+}
+
+func (r *registry) Codes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	codes := make([]string, len(r.codes))
+	copy(codes, r.codes)
+	return codes
+}
@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Query is what a provider needs to know to search its own inventory
+type Query struct {
+	Origin        string
+	Destinations  []string
+	DepartureDate string
+	// Passengers and CabinClass narrow the search, zero asks for every flight of the
+	// route whatever its seats and cabin. The flight service leaves them zero.
+	Passengers int
+	CabinClass string
+}
+
+// FixturePath is where a mock provider keeps the response for one route and date,
+// e.g. mock/garuda/CGK-DPS_2025-12-15.json
+func FixturePath(dir, origin, destination, date string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s-%s_%s.json", origin, destination, date))
+}
+
+// ReadFixtures returns the raw mock response of every destination in the query.
+// A route without a fixture simply has no flights on that date.
+func ReadFixtures(dir string, q Query) ([][]byte, error) {
+	files := make([][]byte, 0, len(q.Destinations))
+
+	for _, dest := range q.Destinations {
+		data, err := os.ReadFile(FixturePath(dir, q.Origin, dest, q.DepartureDate))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, data)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Constructor builds an airline from its options
+type Constructor func(opts Options) Provider
+
+var (
+	constructorsMu sync.RWMutex
+	constructors   = make(map[string]Constructor)
+)
+
+// Register makes an airline available under code. Each airline package calls it
+// from its init, so importing the package is all it takes to enable it in the
+// config. It panics when code is empty or taken, like sql.Register.
+func Register(code string, build Constructor) {
+	constructorsMu.Lock()
+	defer constructorsMu.Unlock()
+
+	if code == "" || build == nil {
+		panic("provider.Register: code and constructor are required")
+	}
+	if _, exists := constructors[code]; exists {
+		panic(fmt.Sprintf("provider.Register: %s registered twice", code))
+	}
+	constructors[code] = build
+}
+
+// Lookup returns the constructor registered under code
+func Lookup(code string) (Constructor, bool) {
+	constructorsMu.RLock()
+	defer constructorsMu.RUnlock()
+
+	build, ok := constructors[code]
+	return build, ok
+}
+
+// Available lists the code of every registered airline, sorted
+func Available() []string {
+	constructorsMu.RLock()
+	defer constructorsMu.RUnlock()
+
+	codes := make([]string, 0, len(constructors))
+	for code := range constructors {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
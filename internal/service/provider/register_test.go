@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	build := func(opts Options) Provider { return nil }
+	Register("TestAir", build)
+
+	if _, ok := Lookup("TestAir"); !ok {
+		t.Fatal("Lookup(TestAir) found nothing")
+	}
+	if _, ok := Lookup("NoAir"); ok {
+		t.Fatal("Lookup(NoAir) found a constructor")
+	}
+	if codes := Available(); !slices.Contains(codes, "TestAir") || !slices.IsSorted(codes) {
+		t.Fatalf("Available() = %v, want TestAir in a sorted list", codes)
+	}
+
+	for _, tt := range []struct {
+		name  string
+		code  string
+		build Constructor
+	}{
+		{"twice", "TestAir", build},
+		{"without a code", "", build},
+		{"without a constructor", "OtherAir", nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("Register(%q) did not panic", tt.code)
+				}
+			}()
+			Register(tt.code, tt.build)
+		})
+	}
+}
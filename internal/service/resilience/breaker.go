@@ -0,0 +1,154 @@
+package resilience
+
+import (
+	"sync"
+	"time"
+)
+
+type State string
+
+const (
+	StateClosed   State = "closed"
+	StateOpen     State = "open"
+	StateHalfOpen State = "half_open"
+)
+
+type BreakerConfig struct {
+	// FailureThreshold consecutive failures open the breaker
+	FailureThreshold int
+	// Cooldown is how long an open breaker rejects calls before letting a trial call through
+	Cooldown time.Duration
+	// HalfOpenSuccesses trial calls must succeed in a row before the breaker closes again
+	HalfOpenSuccesses int
+}
+
+func DefaultBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		FailureThreshold:  5,
+		Cooldown:          30 * time.Second,
+		HalfOpenSuccesses: 1,
+	}
+}
+
+type circuitBreaker struct {
+	mu     sync.Mutex
+	config BreakerConfig
+	now    func() time.Time
+
+	state     State
+	failures  int
+	successes int
+	openedAt  time.Time
+	// only one trial call at a time while half-open
+	trialInFlight bool
+}
+
+// CircuitBreaker stops calling a provider that keeps failing.
+// Closed lets every call through, open rejects every call until the cooldown
+// passes, half-open lets single trial calls through to decide which way to go.
+type CircuitBreaker interface {
+	// Allow reports whether a call may go ahead. Every allowed call must be
+	// followed by Success, Failure or Release.
+	Allow() bool
+	Success()
+	Failure()
+	// Release ends a call abandoned before it said anything about the provider,
+	// it counts neither way
+	Release()
+	State() State
+}
+
+func NewCircuitBreaker(config BreakerConfig) CircuitBreaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 1
+	}
+	if config.HalfOpenSuccesses <= 0 {
+		config.HalfOpenSuccesses = 1
+	}
+
+	return &circuitBreaker{
+		config: config,
+		now:    time.Now,
+		state:  StateClosed,
+	}
+}
+
+func (b *circuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if b.now().Sub(b.openedAt) < b.config.Cooldown {
+			return false
+		}
+		b.state = StateHalfOpen
+		b.successes = 0
+		b.trialInFlight = true
+		return true
+	case StateHalfOpen:
+		if b.trialInFlight {
+			return false
+		}
+		b.trialInFlight = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (b *circuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateHalfOpen:
+		b.trialInFlight = false
+		b.successes++
+		if b.successes >= b.config.HalfOpenSuccesses {
+			b.state = StateClosed
+			b.failures = 0
+		}
+	default:
+		b.failures = 0
+	}
+}
+
+func (b *circuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateHalfOpen:
+		b.trialInFlight = false
+		b.trip()
+	case StateClosed:
+		b.failures++
+		if b.failures >= b.config.FailureThreshold {
+			b.trip()
+		}
+	}
+}
+
+func (b *circuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// a half-open breaker lets the next trial through instead
+	if b.state == StateHalfOpen {
+		b.trialInFlight = false
+	}
+}
+
+func (b *circuitBreaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+func (b *circuitBreaker) trip() {
+	b.state = StateOpen
+	b.openedAt = b.now()
+	b.failures = 0
+	b.successes = 0
+}
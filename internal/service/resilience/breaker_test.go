@@ -0,0 +1,146 @@
+package resilience
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	config := BreakerConfig{FailureThreshold: 3, Cooldown: 30 * time.Second, HalfOpenSuccesses: 2}
+
+	// events: allow and deny call Allow and expect true or false, success, failure and
+	// release end a call, wait lets the cooldown pass. The state is checked after each one.
+	type step struct {
+		event string
+		want  State
+	}
+	opened := []step{
+		{"failure", StateClosed},
+		{"failure", StateClosed},
+		{"failure", StateOpen},
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "closed below the threshold",
+			steps: []step{
+				{"allow", StateClosed},
+				{"failure", StateClosed},
+				{"failure", StateClosed},
+				{"success", StateClosed},
+				{"failure", StateClosed},
+				{"failure", StateClosed},
+				{"allow", StateClosed},
+			},
+		},
+		{
+			name:  "consecutive failures open it",
+			steps: append(opened, step{"deny", StateOpen}),
+		},
+		{
+			name: "open until the cooldown passes",
+			steps: append(opened,
+				step{"deny", StateOpen},
+				step{"wait", StateOpen},
+				step{"allow", StateHalfOpen},
+			),
+		},
+		{
+			name: "half-open lets one trial through at a time",
+			steps: append(opened,
+				step{"wait", StateOpen},
+				step{"allow", StateHalfOpen},
+				step{"deny", StateHalfOpen},
+				step{"success", StateHalfOpen},
+				step{"allow", StateHalfOpen},
+			),
+		},
+		{
+			name: "half-open closes after enough trial successes",
+			steps: append(opened,
+				step{"wait", StateOpen},
+				step{"allow", StateHalfOpen},
+				step{"success", StateHalfOpen},
+				step{"allow", StateHalfOpen},
+				step{"success", StateClosed},
+				step{"allow", StateClosed},
+				step{"allow", StateClosed},
+			),
+		},
+		{
+			name: "a failed trial opens it again for a new cooldown",
+			steps: append(opened,
+				step{"wait", StateOpen},
+				step{"allow", StateHalfOpen},
+				step{"failure", StateOpen},
+				step{"deny", StateOpen},
+				step{"wait", StateOpen},
+				step{"allow", StateHalfOpen},
+			),
+		},
+		{
+			name: "a released call counts neither way",
+			steps: []step{
+				{"failure", StateClosed},
+				{"failure", StateClosed},
+				{"release", StateClosed},
+				{"failure", StateOpen},
+			},
+		},
+		{
+			name: "a released trial lets the next one through",
+			steps: append(opened,
+				step{"wait", StateOpen},
+				step{"allow", StateHalfOpen},
+				step{"deny", StateHalfOpen},
+				step{"release", StateHalfOpen},
+				step{"allow", StateHalfOpen},
+			),
+		},
+		{
+			name: "a closed breaker counts failures from zero again",
+			steps: append(opened,
+				step{"wait", StateOpen},
+				step{"allow", StateHalfOpen},
+				step{"success", StateHalfOpen},
+				step{"allow", StateHalfOpen},
+				step{"success", StateClosed},
+				step{"failure", StateClosed},
+				step{"failure", StateClosed},
+				step{"failure", StateOpen},
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2025, 12, 15, 0, 0, 0, 0, time.UTC)
+			b := NewCircuitBreaker(config).(*circuitBreaker)
+			b.now = func() time.Time { return now }
+
+			for i, s := range tt.steps {
+				switch s.event {
+				case "allow", "deny":
+					if got, want := b.Allow(), s.event == "allow"; got != want {
+						t.Fatalf("step %d: Allow() = %v, want %v", i, got, want)
+					}
+				case "success":
+					b.Success()
+				case "failure":
+					b.Failure()
+				case "release":
+					b.Release()
+				case "wait":
+					now = now.Add(config.Cooldown)
+				}
+
+				if got := b.State(); got != s.want {
+					t.Fatalf("step %d (%s): state = %s, want %s", i, s.event, got, s.want)
+				}
+			}
+		})
+	}
+}
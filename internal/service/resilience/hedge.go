@@ -0,0 +1,143 @@
+package resilience
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+type HedgeConfig struct {
+	Enabled bool
+	// MinSamples successful calls are needed before the percentile is trusted
+	MinSamples int
+	// Percentile (0-100] of the provider's latency after which the second call is fired
+	Percentile float64
+	// MinDelay stops a very fast provider from being hedged on every call
+	MinDelay time.Duration
+}
+
+func DefaultHedgeConfig() HedgeConfig {
+	return HedgeConfig{
+		Enabled:    true,
+		MinSamples: 20,
+		Percentile: 95,
+		MinDelay:   20 * time.Millisecond,
+	}
+}
+
+const latencyWindow = 100
+
+type latencyTracker struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+}
+
+// LatencyTracker keeps the latest call durations of one provider
+type LatencyTracker interface {
+	Observe(d time.Duration)
+	// Percentile returns the p-th percentile (0-100) and how many samples it is based on
+	Percentile(p float64) (time.Duration, int)
+}
+
+func NewLatencyTracker() LatencyTracker {
+	return &latencyTracker{
+		samples: make([]time.Duration, 0, latencyWindow),
+	}
+}
+
+func (t *latencyTracker) Observe(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.samples) < latencyWindow {
+		t.samples = append(t.samples, d)
+		return
+	}
+	t.samples[t.next] = d
+	t.next = (t.next + 1) % latencyWindow
+}
+
+func (t *latencyTracker) Percentile(p float64) (time.Duration, int) {
+	t.mu.Lock()
+	sorted := append([]time.Duration(nil), t.samples...)
+	t.mu.Unlock()
+
+	if len(sorted) == 0 {
+		return 0, 0
+	}
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	index := int(float64(len(sorted)-1) * p / 100)
+	return sorted[index], len(sorted)
+}
+
+// HedgeDelay is how long to wait for the first call before firing a second one.
+// It returns false when hedging is off or there is not enough history yet.
+func (c HedgeConfig) HedgeDelay(tracker LatencyTracker) (time.Duration, bool) {
+	if !c.Enabled {
+		return 0, false
+	}
+
+	percentile := c.Percentile
+	if percentile <= 0 {
+		percentile = DefaultHedgeConfig().Percentile
+	}
+
+	delay, samples := tracker.Percentile(percentile)
+	if samples < c.MinSamples {
+		return 0, false
+	}
+	if delay < c.MinDelay {
+		delay = c.MinDelay
+	}
+	return delay, true
+}
+
+// Hedge calls fn and, when it has not answered after delay, calls it a second
+// time. The first success wins and the other call is cancelled. It reports
+// whether the second call was fired.
+func Hedge[T any](ctx context.Context, delay time.Duration, fn func(ctx context.Context) (T, error)) (T, bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		value T
+		err   error
+	}
+	results := make(chan result, 2)
+	call := func() {
+		value, err := fn(ctx)
+		results <- result{value, err}
+	}
+
+	go call()
+	inFlight := 1
+	hedged := false
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	var last result
+	for {
+		select {
+		case <-timer.C:
+			if !hedged {
+				hedged = true
+				inFlight++
+				go call()
+			}
+		case res := <-results:
+			inFlight--
+			if res.err == nil {
+				return res.value, hedged, nil
+			}
+			last = res
+			// the first call failed before the hedge fired, no reason to wait for it
+			if inFlight == 0 {
+				return last.value, hedged, last.err
+			}
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHedgeFirstAnswerWins(t *testing.T) {
+	var calls atomic.Int32
+	loserCancelled := make(chan struct{})
+
+	value, hedged, err := Hedge(context.Background(), 10*time.Millisecond, func(ctx context.Context) (string, error) {
+		if calls.Add(1) == 1 {
+			// the first call hangs until the hedge wins and cancels it
+			<-ctx.Done()
+			close(loserCancelled)
+			return "", ctx.Err()
+		}
+		return "hedge", nil
+	})
+
+	if err != nil || value != "hedge" {
+		t.Fatalf("Hedge() = %q, %v, want the hedged call's answer", value, err)
+	}
+	if !hedged {
+		t.Error("hedged = false, want true")
+	}
+	select {
+	case <-loserCancelled:
+	case <-time.After(time.Second):
+		t.Error("the losing call was not cancelled")
+	}
+}
+
+func TestHedgeFirstCallWinsWhileTheHedgeRuns(t *testing.T) {
+	var calls atomic.Int32
+	loserCancelled := make(chan struct{})
+
+	value, hedged, err := Hedge(context.Background(), 10*time.Millisecond, func(ctx context.Context) (string, error) {
+		if calls.Add(1) == 1 {
+			time.Sleep(30 * time.Millisecond)
+			return "first", nil
+		}
+		<-ctx.Done()
+		close(loserCancelled)
+		return "", ctx.Err()
+	})
+
+	if err != nil || value != "first" {
+		t.Fatalf("Hedge() = %q, %v, want the first call's answer", value, err)
+	}
+	if !hedged {
+		t.Error("hedged = false, want true")
+	}
+	select {
+	case <-loserCancelled:
+	case <-time.After(time.Second):
+		t.Error("the hedged call was not cancelled")
+	}
+}
+
+func TestHedgeNotFiredForAFastCall(t *testing.T) {
+	var calls atomic.Int32
+
+	value, hedged, err := Hedge(context.Background(), time.Second, func(ctx context.Context) (string, error) {
+		calls.Add(1)
+		return "first", nil
+	})
+
+	if err != nil || value != "first" || hedged {
+		t.Errorf("Hedge() = %q, %v, %v, want the first answer without a hedge", value, hedged, err)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("calls = %d, want 1", n)
+	}
+}
+
+func TestHedgeFailureBeforeTheHedge(t *testing.T) {
+	_, hedged, err := Hedge(context.Background(), time.Second, func(ctx context.Context) (string, error) {
+		return "", errUnavailable
+	})
+
+	if !errors.Is(err, errUnavailable) || hedged {
+		t.Errorf("Hedge() = %v, %v, want the failure at once without a hedge", hedged, err)
+	}
+}
+
+func TestHedgeBothCallsFail(t *testing.T) {
+	var calls atomic.Int32
+
+	_, hedged, err := Hedge(context.Background(), 5*time.Millisecond, func(ctx context.Context) (string, error) {
+		calls.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return "", errUnavailable
+	})
+
+	if !errors.Is(err, errUnavailable) || !hedged {
+		t.Errorf("Hedge() = %v, %v, want the failure after a hedge", hedged, err)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("calls = %d, want 2", n)
+	}
+}
+
+func TestLatencyTrackerPercentile(t *testing.T) {
+	tracker := NewLatencyTracker()
+	if p95, samples := tracker.Percentile(95); p95 != 0 || samples != 0 {
+		t.Errorf("empty tracker Percentile(95) = %v, %d, want 0, 0", p95, samples)
+	}
+
+	// observed out of order on purpose
+	for i := 100; i >= 1; i-- {
+		tracker.Observe(time.Duration(i) * time.Millisecond)
+	}
+
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{0, 1 * time.Millisecond},
+		{50, 50 * time.Millisecond},
+		{95, 95 * time.Millisecond},
+		{100, 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		got, samples := tracker.Percentile(tt.p)
+		if got != tt.want || samples != 100 {
+			t.Errorf("Percentile(%v) = %v, %d, want %v, 100", tt.p, got, samples, tt.want)
+		}
+	}
+}
+
+func TestLatencyTrackerKeepsTheLatestWindow(t *testing.T) {
+	tracker := NewLatencyTracker()
+	for i := 1; i <= latencyWindow+50; i++ {
+		tracker.Observe(time.Duration(i) * time.Millisecond)
+	}
+
+	// the 50 oldest samples were overwritten
+	lowest, samples := tracker.Percentile(0)
+	if lowest != 51*time.Millisecond || samples != latencyWindow {
+		t.Errorf("Percentile(0) = %v, %d, want 51ms, %d", lowest, samples, latencyWindow)
+	}
+	if highest, _ := tracker.Percentile(100); highest != 150*time.Millisecond {
+		t.Errorf("Percentile(100) = %v, want 150ms", highest)
+	}
+}
+
+func TestHedgeDelay(t *testing.T) {
+	tracker := func(samples int, latency time.Duration) LatencyTracker {
+		tr := NewLatencyTracker()
+		for i := 0; i < samples; i++ {
+			tr.Observe(latency)
+		}
+		return tr
+	}
+	// 1ms to 100ms
+	spread := NewLatencyTracker()
+	for i := 1; i <= 100; i++ {
+		spread.Observe(time.Duration(i) * time.Millisecond)
+	}
+
+	tests := []struct {
+		name      string
+		config    HedgeConfig
+		tracker   LatencyTracker
+		wantDelay time.Duration
+		wantOK    bool
+	}{
+		{
+			name:      "p95 once there are enough samples",
+			config:    HedgeConfig{Enabled: true, MinSamples: 20, Percentile: 95, MinDelay: 20 * time.Millisecond},
+			tracker:   tracker(20, 300*time.Millisecond),
+			wantDelay: 300 * time.Millisecond,
+			wantOK:    true,
+		},
+		{
+			name:    "not before MinSamples",
+			config:  HedgeConfig{Enabled: true, MinSamples: 20, Percentile: 95, MinDelay: 20 * time.Millisecond},
+			tracker: tracker(19, 300*time.Millisecond),
+		},
+		{
+			name:      "never below MinDelay",
+			config:    HedgeConfig{Enabled: true, MinSamples: 20, Percentile: 95, MinDelay: 20 * time.Millisecond},
+			tracker:   tracker(20, 5*time.Millisecond),
+			wantDelay: 20 * time.Millisecond,
+			wantOK:    true,
+		},
+		{
+			name:      "configured percentile",
+			config:    HedgeConfig{Enabled: true, MinSamples: 20, Percentile: 50, MinDelay: 20 * time.Millisecond},
+			tracker:   spread,
+			wantDelay: 50 * time.Millisecond,
+			wantOK:    true,
+		},
+		{
+			name:      "p95 when no percentile is set",
+			config:    HedgeConfig{Enabled: true, MinSamples: 20, MinDelay: 20 * time.Millisecond},
+			tracker:   spread,
+			wantDelay: 95 * time.Millisecond,
+			wantOK:    true,
+		},
+		{
+			name:    "disabled",
+			config:  HedgeConfig{Enabled: false, MinSamples: 20, Percentile: 95, MinDelay: 20 * time.Millisecond},
+			tracker: tracker(20, 300*time.Millisecond),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, ok := tt.config.HedgeDelay(tt.tracker)
+			if delay != tt.wantDelay || ok != tt.wantOK {
+				t.Errorf("HedgeDelay() = %v, %v, want %v, %v", delay, ok, tt.wantDelay, tt.wantOK)
+			}
+		})
+	}
+}
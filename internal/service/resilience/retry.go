@@ -0,0 +1,75 @@
+package resilience
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+type RetryConfig struct {
+	// MaxAttempts includes the first call, 1 disables retries
+	MaxAttempts int
+	// BaseDelay doubles on every retry up to MaxDelay, the actual wait is a random value below it
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts: 3,
+		BaseDelay:   50 * time.Millisecond,
+		MaxDelay:    400 * time.Millisecond,
+	}
+}
+
+// jitter picks the wait in [0, n), replaced in tests to make it predictable
+var jitter = rand.Int63n
+
+// Backoff is the full-jitter wait before the given retry (1 for the first retry)
+func (c RetryConfig) Backoff(retry int) time.Duration {
+	ceiling := c.BaseDelay << (retry - 1)
+	if ceiling <= 0 || ceiling > c.MaxDelay {
+		ceiling = c.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(jitter(int64(ceiling) + 1))
+}
+
+// Retry calls fn until it succeeds or the attempts run out. It never waits past
+// the deadline of ctx: when the next backoff would not fit, it gives up early.
+// It returns how many retries were made on top of the first call.
+func Retry(ctx context.Context, config RetryConfig, fn func(ctx context.Context) error) (int, error) {
+	attempts := config.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			delay := config.Backoff(attempt)
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+				return attempt - 1, err
+			}
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return attempt - 1, err
+			case <-timer.C:
+			}
+		}
+
+		if err = fn(ctx); err == nil {
+			return attempt, nil
+		}
+		if ctx.Err() != nil {
+			return attempt, err
+		}
+	}
+
+	return attempts - 1, err
+}
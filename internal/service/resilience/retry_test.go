@@ -0,0 +1,138 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+var errUnavailable = errors.New("provider unavailable")
+
+// fullBackoff makes every backoff wait its whole ceiling for the duration of the test
+func fullBackoff(t *testing.T) {
+	t.Helper()
+	jitter = func(n int64) int64 { return n - 1 }
+	t.Cleanup(func() { jitter = rand.Int63n })
+}
+
+func TestRetry(t *testing.T) {
+	fullBackoff(t)
+	config := RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 4 * time.Millisecond}
+
+	tests := []struct {
+		name        string
+		failures    int
+		maxAttempts int
+		wantCalls   int
+		wantRetries int
+		wantErr     error
+	}{
+		{name: "first call succeeds", failures: 0, maxAttempts: 3, wantCalls: 1, wantRetries: 0},
+		{name: "succeeds on a retry", failures: 2, maxAttempts: 3, wantCalls: 3, wantRetries: 2},
+		{name: "attempts run out", failures: 5, maxAttempts: 3, wantCalls: 3, wantRetries: 2, wantErr: errUnavailable},
+		{name: "one attempt disables retries", failures: 1, maxAttempts: 1, wantCalls: 1, wantRetries: 0, wantErr: errUnavailable},
+		{name: "zero attempts still calls once", failures: 0, maxAttempts: 0, wantCalls: 1, wantRetries: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.MaxAttempts = tt.maxAttempts
+			calls := 0
+			retries, err := Retry(context.Background(), config, func(ctx context.Context) error {
+				calls++
+				if calls <= tt.failures {
+					return errUnavailable
+				}
+				return nil
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if retries != tt.wantRetries {
+				t.Errorf("retries = %d, want %d", retries, tt.wantRetries)
+			}
+		})
+	}
+}
+
+func TestRetryStopsAtTheBudgetDeadline(t *testing.T) {
+	fullBackoff(t)
+
+	tests := []struct {
+		name        string
+		config      RetryConfig
+		budget      time.Duration
+		wantCalls   int
+		wantRetries int
+	}{
+		{
+			// the first backoff does not fit in the budget, waiting for it would only hit the deadline
+			name:        "no retry when the backoff outlasts the budget",
+			config:      RetryConfig{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Second},
+			budget:      100 * time.Millisecond,
+			wantCalls:   1,
+			wantRetries: 0,
+		},
+		{
+			// 40ms then 80ms of backoff, the second one no longer fits
+			name:        "stops once the next backoff outlasts the budget",
+			config:      RetryConfig{MaxAttempts: 5, BaseDelay: 40 * time.Millisecond, MaxDelay: time.Second},
+			budget:      100 * time.Millisecond,
+			wantCalls:   2,
+			wantRetries: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), tt.budget)
+			defer cancel()
+
+			start := time.Now()
+			calls := 0
+			retries, err := Retry(ctx, tt.config, func(ctx context.Context) error {
+				calls++
+				return errUnavailable
+			})
+			elapsed := time.Since(start)
+
+			if !errors.Is(err, errUnavailable) {
+				t.Errorf("err = %v, want the last call's error", err)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if retries != tt.wantRetries {
+				t.Errorf("retries = %d, want %d", retries, tt.wantRetries)
+			}
+			if elapsed >= tt.budget {
+				t.Errorf("returned after %v, want before the %v budget ran out", elapsed, tt.budget)
+			}
+		})
+	}
+}
+
+func TestRetryStopsWhenACallRunsOutOfTime(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	retries, err := Retry(ctx, DefaultRetryConfig(), func(ctx context.Context) error {
+		calls++
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if calls != 1 || retries != 0 {
+		t.Errorf("calls = %d, retries = %d, want 1 call and no retry", calls, retries)
+	}
+}
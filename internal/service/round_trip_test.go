@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"flight-aggregator/internal/entity"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPickRoundTrips(t *testing.T) {
+	day := func(d, hour int) time.Time { return time.Date(2025, 12, d, hour, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name          string
+		outbound      []entity.Flight
+		inbound       []entity.Flight
+		wantCheapest  []string
+		wantBestValue []string
+	}{
+		{
+			name:          "no return flight, no pair",
+			outbound:      []entity.Flight{legFlight("OUT", "Garuda Indonesia", "CGK", "DPS", day(15, 6), 120, 1000000)},
+			wantCheapest:  nil,
+			wantBestValue: nil,
+		},
+		{
+			name: "the cheapest legs pair up",
+			outbound: []entity.Flight{
+				legFlight("OUT1", "Garuda Indonesia", "CGK", "DPS", day(15, 6), 120, 1000000),
+				legFlight("OUT2", "Garuda Indonesia", "CGK", "DPS", day(15, 9), 120, 800000),
+			},
+			inbound: []entity.Flight{
+				legFlight("IN1", "Garuda Indonesia", "DPS", "CGK", day(18, 6), 120, 900000),
+				legFlight("IN2", "Garuda Indonesia", "DPS", "CGK", day(18, 9), 120, 700000),
+			},
+			wantCheapest:  []string{"OUT2", "IN2"},
+			wantBestValue: []string{"OUT2", "IN2"},
+		},
+		{
+			name: "a return leaving before the outbound lands is skipped",
+			outbound: []entity.Flight{
+				legFlight("OUT", "Garuda Indonesia", "CGK", "DPS", day(15, 6), 120, 1000000),
+			},
+			inbound: []entity.Flight{
+				legFlight("EARLY", "Garuda Indonesia", "DPS", "CGK", day(15, 7), 120, 100000),
+				legFlight("LATE", "Garuda Indonesia", "DPS", "CGK", day(15, 12), 120, 900000),
+			},
+			wantCheapest:  []string{"OUT", "LATE"},
+			wantBestValue: []string{"OUT", "LATE"},
+		},
+		{
+			name: "best value trades price for flying time",
+			outbound: []entity.Flight{
+				legFlight("SLOW", "Garuda Indonesia", "CGK", "DPS", day(15, 6), 600, 900000),
+				legFlight("FAST", "Garuda Indonesia", "CGK", "DPS", day(15, 6), 120, 1000000),
+			},
+			inbound: []entity.Flight{
+				legFlight("IN", "Garuda Indonesia", "DPS", "CGK", day(18, 6), 120, 700000),
+			},
+			wantCheapest:  []string{"SLOW", "IN"},
+			wantBestValue: []string{"FAST", "IN"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTestService(t, newMemCache())
+			req := entity.SearchRequest{}
+			f.standardizeRequest(&req)
+			f.applyPassengerPricing(tt.outbound, req)
+			f.applyPassengerPricing(tt.inbound, req)
+
+			cheapest, bestValue := f.pickRoundTrips(tt.outbound, tt.inbound)
+			if got := itineraryIDs(cheapest); !slices.Equal(got, tt.wantCheapest) {
+				t.Errorf("cheapest = %v, want %v", got, tt.wantCheapest)
+			}
+			if got := itineraryIDs(bestValue); !slices.Equal(got, tt.wantBestValue) {
+				t.Errorf("best value = %v, want %v", got, tt.wantBestValue)
+			}
+		})
+	}
+}
+
+func TestSearchFlightRoundTrip(t *testing.T) {
+	garuda := &fakeProvider{code: "Garuda", name: "Garuda Indonesia", flights: map[string][]entity.Flight{
+		routeKey("CGK", "DPS", "2025-12-15"): {
+			legFlight("GA400", "Garuda Indonesia", "CGK", "DPS", time.Date(2025, 12, 15, 6, 0, 0, 0, time.UTC), 120, 1250000),
+		},
+		routeKey("DPS", "CGK", "2025-12-18"): {
+			legFlight("GA401", "Garuda Indonesia", "DPS", "CGK", time.Date(2025, 12, 18, 6, 0, 0, 0, time.UTC), 120, 1100000),
+		},
+	}}
+	f := newTestService(t, newMemCache(), garuda)
+
+	returnDate := "2025-12-18"
+	res, err := f.SearchFlight(context.Background(), entity.SearchRequest{
+		Origin:        "CGK",
+		Destination:   []string{"DPS"},
+		DepartureDate: "2025-12-15",
+		ReturnDate:    &returnDate,
+		Passanger:     2,
+	})
+	if err != nil {
+		t.Fatalf("SearchFlight: %v", err)
+	}
+
+	if got := flightIDs(res.Flights); !slices.Equal(got, []string{"GA400"}) {
+		t.Errorf("flights = %v, want [GA400]", got)
+	}
+	if got := flightIDs(res.ReturnFlights); !slices.Equal(got, []string{"GA401"}) {
+		t.Errorf("return flights = %v, want [GA401]", got)
+	}
+	if res.CheapestRoundTrip == nil {
+		t.Fatal("no cheapest round trip")
+	}
+	price := res.CheapestRoundTrip.TotalPrice
+	if price.Amount != 2350000 || price.TotalAmount != 4700000 || price.Passengers != 2 {
+		t.Errorf("price = %v a seat, %v for %d, want 2350000 a seat, 4700000 for 2", price.Amount, price.TotalAmount, price.Passengers)
+	}
+	if got := res.CheapestRoundTrip.TotalDuration.TotalMinutes; got != 240 {
+		t.Errorf("duration = %d minutes, want 240", got)
+	}
+	// one airline asked for both legs
+	if res.Metadata.ProvidersQueried != 1 || garuda.calls() != 2 {
+		t.Errorf("providers queried = %d with %d calls, want 1 with 2", res.Metadata.ProvidersQueried, garuda.calls())
+	}
+}
+
+func itineraryIDs(itinerary *entity.Itinerary) []string {
+	if itinerary == nil {
+		return nil
+	}
+	return flightIDs(itinerary.Legs)
+}
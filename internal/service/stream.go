@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"flight-aggregator/internal/entity"
+	"flight-aggregator/internal/service/provider"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// providerAnswer is one provider's flights for a streaming search, from the cache or live
+type providerAnswer struct {
+	code        string
+	flights     []entity.Flight
+	status      string
+	cacheStatus string
+	retries     int
+}
+
+// SearchFlightStream runs a one-way search like SearchFlight but hands over each provider's
+// flights as soon as they arrive. The channel carries one provider event per provider, a
+// best value event whenever the best deal so far changes and a final summary event, then
+// it is closed. Cancelling ctx stops the stream.
+func (f *flightService) SearchFlightStream(ctx context.Context, req entity.SearchRequest) (<-chan entity.SearchEvent, error) {
+	startTime := time.Now()
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", entity.ErrInvalidRequest, err)
+	}
+	if req.IsRoundTrip() {
+		return nil, fmt.Errorf("%w: returnDate is not supported when streaming, stream each leg on its own", entity.ErrInvalidRequest)
+	}
+	if err := f.checkDisplayCurrency(req.DisplayCurrency); err != nil {
+		return nil, err
+	}
+	f.standardizeRequest(&req)
+
+	events := make(chan entity.SearchEvent)
+	go f.streamLeg(ctx, req, startTime, events)
+	return events, nil
+}
+
+func (f *flightService) streamLeg(ctx context.Context, req entity.SearchRequest, startTime time.Time, events chan<- entity.SearchEvent) {
+	defer close(events)
+
+	// the stream gives up when the caller goes away
+	send := func(event entity.SearchEvent) bool {
+		select {
+		case events <- event:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	// providers are only waited for until the search deadline
+	searchCtx, cancel := f.withSearchDeadline(ctx, req.MaxWaitMs)
+	defer cancel()
+
+	var providers []provider.Provider
+	for _, code := range f.targetAirlines(req) {
+		if p, exists := f.providers.Get(code); exists {
+			providers = append(providers, p)
+		}
+	}
+
+	// buffered so providers never block on a caller that left
+	answers := make(chan providerAnswer, len(providers))
+	for _, p := range providers {
+		go func(p provider.Provider) {
+			answers <- f.answerProvider(searchCtx, req, p)
+		}(p)
+	}
+
+	leg := legResult{
+		flights:     []entity.Flight{},
+		statuses:    make(map[string]string),
+		retries:     make(map[string]int),
+		cacheStatus: make(map[string]string),
+	}
+	bestScore := math.MaxFloat64
+
+	for range providers {
+		answer := <-answers
+		flights, bestValue := f.prepareFlights(ctx, answer.flights, req)
+
+		leg.cacheStatus[answer.code] = answer.cacheStatus
+		if answer.retries > 0 {
+			leg.retries[answer.code] = answer.retries
+		}
+		leg.statuses[answer.code] = answer.status
+		switch answer.status {
+		case entity.PROVIDER_SKIPPED:
+			leg.skipped = append(leg.skipped, answer.code)
+		case entity.PROVIDER_TIMED_OUT:
+			leg.timedOut = append(leg.timedOut, answer.code)
+		}
+		leg.flights = append(leg.flights, flights...)
+
+		if !send(entity.SearchEvent{
+			Type: entity.EVENT_PROVIDER,
+			Provider: &entity.ProviderResult{
+				Provider:    answer.code,
+				Status:      answer.status,
+				CacheStatus: answer.cacheStatus,
+				Retries:     answer.retries,
+				Flights:     flights,
+			},
+		}) {
+			return
+		}
+
+		if bestValue == nil {
+			continue
+		}
+		if score := f.bestValueScore(*bestValue); score < bestScore {
+			bestScore = score
+			leg.bestValue = bestValue
+			if !send(entity.SearchEvent{Type: entity.EVENT_BEST_VALUE, BestValue: bestValue}) {
+				return
+			}
+		}
+	}
+
+	summary := entity.SearchSummary{
+		SearchCriteria: newSearchCriteria(req),
+		Metadata:       f.summarizeLegs(leg),
+		BestValue:      leg.bestValue,
+	}
+	summary.Metadata.SearchTimeMs = time.Since(startTime).Milliseconds()
+	send(entity.SearchEvent{Type: entity.EVENT_SUMMARY, Summary: &summary})
+}
+
+// answerProvider serves one provider from the cache, or live when the cache misses
+func (f *flightService) answerProvider(ctx context.Context, req entity.SearchRequest, p provider.Provider) providerAnswer {
+	code := p.Code()
+
+	cached, cacheStatus := f.readCache(ctx, req, code)
+	f.logger.DebugContext(ctx, "cache lookup", "provider", code, "status", cacheStatus)
+	if cacheStatus == entity.CACHE_STALE {
+		// served stale now, refreshed for the next search
+		go f.fetchSpecificAirlines(context.WithoutCancel(ctx), req, []string{code})
+	}
+	if cacheStatus != entity.CACHE_MISS {
+		return providerAnswer{code: code, flights: cached, status: entity.PROVIDER_OK, cacheStatus: cacheStatus}
+	}
+
+	res, status := f.fetchShared(ctx, req, p)
+	return providerAnswer{
+		code: code,
+		// a shared fetch hands the same slice to every search, prices are converted in place
+		flights:     slices.Clone(res.flights),
+		status:      status,
+		cacheStatus: entity.CACHE_MISS,
+		retries:     res.retries,
+	}
+}
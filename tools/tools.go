@@ -0,0 +1,5 @@
+// Package tools pins the code generators of the repo. They run from this module,
+// so their dependencies stay out of the application's go.mod:
+//
+//	go tool -modfile=tools/go.mod buf --version
+package tools